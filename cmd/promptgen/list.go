@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/query"
+	"github.com/renatogalera/promptgen/internal/storage/yaml"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List prompts, optionally narrowed by a search query",
+	Long: `List the prompts in the library with their id and tags.
+
+--query narrows the list with the same syntax as the search in the interface:
+  tag:go -tag:draft title:review var:project_name has:doc "exact phrase"
+Terms separated by spaces must all match; join terms with OR (or |) to match
+any of them, and group with parentheses.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		promptFile, _ := cmd.Flags().GetString("file")
+		queryText, _ := cmd.Flags().GetString("query")
+
+		q, err := query.Parse(queryText)
+		if err != nil {
+			return err
+		}
+
+		collection, err := yaml.NewRepository(resolvePromptFilePath(promptFile), prompt.NewService()).LoadPrompts()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "TITLE\tID\tTAGS")
+		for _, p := range q.Filter(collection.Prompts) {
+			id := p.ID
+			if id == "" {
+				id = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", p.Title, id, strings.Join(p.Tags, ", "))
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	listCmd.Flags().StringP("query", "q", "", "Only list prompts matching the search query")
+	rootCmd.AddCommand(listCmd)
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
 	"github.com/renatogalera/promptgen/internal/app"
@@ -20,7 +21,7 @@ func main() {
 }
 
 var rootCmd = &cobra.Command{
-	Use:   "promptgen[flags]",
+	Use:   "promptgen [flags]",
 	Short: "Interactive AI prompt management system in your terminal",
 	Long: `promptgenhelps you organize, search, and use your AI prompts efficiently.
 Load prompts from a YAML file, browse, search (press '/'), view details,
@@ -31,16 +32,47 @@ and copy prompts formatted as XML to the clipboard. Create new prompts using 'n'
 
 		promptFile = resolvePromptFilePath(promptFile)
 
-		application := app.NewApplication(promptFile)
-		p := tea.NewProgram(application, tea.WithAltScreen(), tea.WithMouseCellMotion())
+		settings := loadSettings()
+		if backend, _ := cmd.Flags().GetString("clipboard"); backend != "" {
+			settings.Clipboard.Backend = backend
+		}
+
+		printOutput, _ := cmd.Flags().GetBool("print")
+		programOptions := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+		if printOutput {
+			tty, err := openTTY()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			defer tty.Close()
+			programOptions = append(programOptions, tea.WithInput(tty), tea.WithOutput(tty))
+		}
+
+		application := app.NewApplication(promptFile, settings, app.Options{Print: printOutput})
+		p := tea.NewProgram(application, programOptions...)
 
 		if _, err := p.Run(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 			os.Exit(1)
 		}
+		if output := application.Output(); output != "" {
+			fmt.Println(output)
+		}
 	},
 }
 
+// openTTY opens the controlling terminal so the TUI can be drawn there while
+// stdout is piped, and makes lipgloss pick its colors from it.
+func openTTY() (*os.File, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the terminal: %w", err)
+	}
+	lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(tty))
+	return tty, nil
+}
+
 func resolvePromptFilePath(promptFile string) string {
 
 	if promptFile != "" {
@@ -55,12 +87,10 @@ func resolvePromptFilePath(promptFile string) string {
 		return promptFile
 	}
 
-	home, err := os.UserHomeDir()
+	configDir, err := config.Dir()
 	if err != nil {
-		log.Fatalf("Error: Could not resolve home directory: %v\n", err)
+		log.Fatalf("Error: %v\n", err)
 	}
-
-	configDir := filepath.Join(home, ".config", config.ConfigDirName)
 	configPath := filepath.Join(configDir, config.DefaultPromptFilename)
 
 	if _, err := os.Stat(configPath); err == nil {
@@ -75,6 +105,14 @@ func resolvePromptFilePath(promptFile string) string {
 	return promptFile
 }
 
+func loadSettings() config.Settings {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using default settings\n", err)
+	}
+	return settings
+}
+
 func ensureDirectoryExists(dir string) {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		log.Fatalf("Error: Could not create directory '%s': %v\n", dir, err)
@@ -87,5 +125,7 @@ func init() {
 		config.ConfigDirName,
 		config.DefaultPromptFilename)
 
-	rootCmd.Flags().StringP("file", "f", "", "Path to the prompts YAML file (default: "+defaultPathDesc+")")
+	rootCmd.PersistentFlags().StringP("file", "f", "", "Path to the prompts YAML file (default: "+defaultPathDesc+")")
+	rootCmd.Flags().String("clipboard", "", "Clipboard backend: auto, native, osc52, file or command (overrides the config file); use --print for stdout")
+	rootCmd.Flags().Bool("print", false, "Print the rendered prompt to stdout instead of copying it, drawing the TUI on the terminal")
 }
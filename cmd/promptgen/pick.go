@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/promptgen/internal/app"
+	"github.com/renatogalera/promptgen/internal/domain/render"
+)
+
+// exitCancelled is the exit status of pick when the user quits without
+// choosing a prompt, as a shell reports a command ended by Ctrl+C.
+const exitCancelled = 130
+
+var pickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Choose a prompt in a minimal TUI and print it to stdout",
+	Long: `Show the prompt list, and the variable form when the chosen prompt has
+variables, then print the rendered prompt to stdout, or to the file descriptor
+given with --fd, and exit. The interface is drawn on the terminal so the output
+stays clean for pipes and command substitution.
+
+Exits with status 0 when a prompt was printed and 130 when the picker was
+cancelled with Esc, q or Ctrl+C.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		promptFile, _ := cmd.Flags().GetString("file")
+		formatName, _ := cmd.Flags().GetString("format")
+		fd, _ := cmd.Flags().GetInt("fd")
+
+		format, err := render.ParseFormat(formatName)
+		if err != nil {
+			return err
+		}
+
+		out, err := outputFile(fd)
+		if err != nil {
+			return err
+		}
+
+		tty, err := openTTY()
+		if err != nil {
+			return err
+		}
+		defer tty.Close()
+
+		application := app.NewApplication(resolvePromptFilePath(promptFile), loadSettings(), app.Options{Pick: true, Format: format})
+		p := tea.NewProgram(application, tea.WithAltScreen(), tea.WithInput(tty), tea.WithOutput(tty))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("running picker: %w", err)
+		}
+
+		output := application.Output()
+		if output == "" {
+			tty.Close()
+			os.Exit(exitCancelled)
+		}
+		_, err = fmt.Fprintln(out, output)
+		return err
+	},
+}
+
+// outputFile returns the writer for a file descriptor inherited from the
+// shell, such as 3 in `promptgen pick --fd 3 3>prompt.xml`.
+func outputFile(fd int) (io.Writer, error) {
+	switch fd {
+	case 1:
+		return os.Stdout, nil
+	case 2:
+		return os.Stderr, nil
+	}
+	if fd < 0 {
+		return nil, fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("fd%d", fd))
+	if f == nil {
+		return nil, fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	if _, err := f.Stat(); err != nil {
+		return nil, fmt.Errorf("file descriptor %d is not open: %w", fd, err)
+	}
+	return f, nil
+}
+
+func init() {
+	pickCmd.Flags().String("format", string(render.FormatXML), "Output format: xml or markdown")
+	pickCmd.Flags().Int("fd", 1, "File descriptor to write the rendered prompt to")
+	rootCmd.AddCommand(pickCmd)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/promptgen/internal/app"
+	"github.com/renatogalera/promptgen/internal/config"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/render"
+	"github.com/renatogalera/promptgen/internal/domain/stats"
+	"github.com/renatogalera/promptgen/internal/storage/analytics"
+	"github.com/renatogalera/promptgen/internal/storage/yaml"
+	"github.com/renatogalera/promptgen/pkg/clipboard"
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render <prompt>",
+	Short: "Render a prompt non-interactively and print it to stdout",
+	Long: `Render a prompt, referenced by id or title, and print the result to stdout.
+
+Variable values are given with --var name=value. A value of @- reads the
+variable from stdin and @path reads it from a file; use @@ for a literal
+leading @. Variables with 'source: clipboard' that are not given on the
+command line are read from the clipboard. --preset starts from the values of a
+preset saved with the prompt.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		promptFile, _ := cmd.Flags().GetString("file")
+		formatName, _ := cmd.Flags().GetString("format")
+		varFlags, _ := cmd.Flags().GetStringArray("var")
+		allowCommands, _ := cmd.Flags().GetBool("allow-commands")
+		presetName, _ := cmd.Flags().GetString("preset")
+
+		format, err := render.ParseFormat(formatName)
+		if err != nil {
+			return err
+		}
+
+		vars, err := parseVarFlags(varFlags, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+
+		promptService := prompt.NewService()
+		collection, err := yaml.NewRepository(resolvePromptFilePath(promptFile), promptService).LoadPrompts()
+		if err != nil {
+			return err
+		}
+
+		p, ok := collection.Find(args[0])
+		if !ok {
+			return fmt.Errorf("prompt '%s' not found", args[0])
+		}
+
+		if presetName != "" {
+			preset, ok := p.Preset(presetName)
+			if !ok {
+				return fmt.Errorf("prompt '%s' has no preset '%s'", p.Title, presetName)
+			}
+			for name, value := range preset.Values {
+				if _, set := vars[name]; !set {
+					vars[name] = value
+				}
+			}
+		}
+
+		if err := fillFromClipboard(p, vars, clipboard.New()); err != nil {
+			return err
+		}
+
+		settings := loadSettings()
+		renderer, err := app.NewRenderer(promptService, settings)
+		if err != nil {
+			return err
+		}
+		if allowCommands {
+			renderer.ApproveCommands(p)
+		}
+
+		result, err := renderer.Render(p, vars, format)
+		var untrusted *render.UntrustedError
+		if errors.As(err, &untrusted) {
+			return fmt.Errorf("%w\nadd the prompts file to trusted_sources in the config file or pass --allow-commands", err)
+		}
+		for _, note := range result.Notes {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", note)
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintln(cmd.OutOrStdout(), result.Output); err != nil {
+			return err
+		}
+
+		if settings.Analytics.Enabled {
+			event := stats.Event{Time: time.Now(), Type: stats.EventRendered, Prompt: p.Key(), Format: string(format), Variables: len(vars)}
+			if err := analytics.NewLog(config.EventsPath()).Append(event); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		return nil
+	},
+}
+
+// parseVarFlags turns name=value pairs into variable values, reading @- from
+// stdin and @path from files.
+func parseVarFlags(flags []string, stdin io.Reader) (map[string]string, error) {
+	vars := make(map[string]string, len(flags))
+	stdinUsed := false
+
+	for _, flag := range flags {
+		name, value, ok := strings.Cut(flag, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --var '%s': expected name=value", flag)
+		}
+		name = strings.TrimSpace(name)
+
+		switch {
+		case strings.HasPrefix(value, "@@"):
+			value = value[1:]
+		case value == "@-":
+			if stdinUsed {
+				return nil, fmt.Errorf("--var %s: stdin can only be read by one variable", name)
+			}
+			stdinUsed = true
+			data, err := io.ReadAll(stdin)
+			if err != nil {
+				return nil, fmt.Errorf("--var %s: failed to read stdin: %w", name, err)
+			}
+			value = strings.TrimRight(string(data), "\n")
+		case strings.HasPrefix(value, "@"):
+			data, err := os.ReadFile(value[1:])
+			if err != nil {
+				return nil, fmt.Errorf("--var %s: %w", name, err)
+			}
+			value = strings.TrimRight(string(data), "\n")
+		}
+
+		vars[name] = value
+	}
+
+	return vars, nil
+}
+
+func fillFromClipboard(p prompt.Prompt, vars map[string]string, clipboardMgr *clipboard.Manager) error {
+	for _, v := range p.Variables {
+		if _, set := vars[v.Name]; set || !v.FromClipboard() {
+			continue
+		}
+		text, err := clipboardMgr.Paste()
+		if err != nil {
+			return fmt.Errorf("variable '%s': failed to read clipboard: %w", v.Name, err)
+		}
+		vars[v.Name] = strings.TrimRight(text, "\n")
+	}
+	return nil
+}
+
+func init() {
+	renderCmd.Flags().StringArray("var", nil, "Variable value as name=value, name=@- (stdin) or name=@path (file)")
+	renderCmd.Flags().String("format", string(render.FormatXML), "Output format: xml or markdown")
+	renderCmd.Flags().String("preset", "", "Use the values of a preset saved with the prompt; --var overrides them")
+	renderCmd.Flags().Bool("allow-commands", false, "Run the prompt's command variables even if its source is not trusted")
+	rootCmd.AddCommand(renderCmd)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVarFlags(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "diff.txt")
+	if err := os.WriteFile(file, []byte("+ added line\n\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		flags   []string
+		stdin   string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "values",
+			flags: []string{"lang=go", " code =x=1", "empty="},
+			want:  map[string]string{"lang": "go", "code": "x=1", "empty": ""},
+		},
+		{
+			name:  "stdin",
+			flags: []string{"code=@-"},
+			stdin: "func main() {}\n\n",
+			want:  map[string]string{"code": "func main() {}"},
+		},
+		{
+			name:  "file",
+			flags: []string{"diff=@" + file},
+			want:  map[string]string{"diff": "+ added line"},
+		},
+		{
+			name:  "literal @",
+			flags: []string{"user=@@octocat", "at=@@-"},
+			want:  map[string]string{"user": "@octocat", "at": "@-"},
+		},
+		{
+			name:  "the last value wins",
+			flags: []string{"lang=go", "lang=rust"},
+			want:  map[string]string{"lang": "rust"},
+		},
+		{name: "no value", flags: []string{"lang"}, wantErr: "expected name=value"},
+		{name: "no name", flags: []string{" =go"}, wantErr: "expected name=value"},
+		{name: "stdin twice", flags: []string{"a=@-", "b=@-"}, wantErr: "stdin can only be read by one variable"},
+		{name: "missing file", flags: []string{"diff=@" + filepath.Join(dir, "nope.txt")}, wantErr: "--var diff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVarFlags(tt.flags, strings.NewReader(tt.stdin))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseVarFlags(%q) error = %v, want %q", tt.flags, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseVarFlags(%q) error: %v", tt.flags, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseVarFlags(%q) = %q, want %q", tt.flags, got, tt.want)
+			}
+		})
+	}
+}
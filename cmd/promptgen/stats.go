@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/stats"
+	"github.com/renatogalera/promptgen/internal/storage/analytics"
+	"github.com/renatogalera/promptgen/internal/storage/yaml"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show which prompts are used, from the local usage events",
+	Long: `Summarise the usage events recorded on this machine: the most used
+prompts, prompts not used recently, usage per tag and per week.
+
+Events are only recorded when analytics are enabled in the config file:
+
+  analytics:
+    enabled: true`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		promptFile, _ := cmd.Flags().GetString("file")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		opts := stats.DefaultOptions
+		opts.Top, _ = cmd.Flags().GetInt("top")
+		opts.UnusedDays, _ = cmd.Flags().GetInt("days")
+		opts.Weeks, _ = cmd.Flags().GetInt("weeks")
+
+		if !loadSettings().Analytics.Enabled {
+			fmt.Fprintln(os.Stderr, "Warning: analytics are disabled; set analytics.enabled: true in the config file to record uses")
+		}
+
+		events, err := analytics.NewLog(config.EventsPath()).Load()
+		if err != nil {
+			return err
+		}
+		collection, err := yaml.NewRepository(resolvePromptFilePath(promptFile), prompt.NewService()).LoadPrompts()
+		if err != nil {
+			return err
+		}
+
+		report := stats.Compute(events, collection.Prompts, time.Now(), opts)
+		if asJSON {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(report)
+		}
+		report.WriteText(cmd.OutOrStdout())
+		return nil
+	},
+}
+
+func init() {
+	statsCmd.Flags().Bool("json", false, "Print the report as JSON")
+	statsCmd.Flags().Int("top", stats.DefaultOptions.Top, "Number of top prompts to show")
+	statsCmd.Flags().Int("days", stats.DefaultOptions.UnusedDays, "List prompts not used in this many days")
+	statsCmd.Flags().Int("weeks", stats.DefaultOptions.Weeks, "Number of weeks in the weekly trend")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"errors"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -9,8 +11,16 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/docs"
+	"github.com/renatogalera/promptgen/internal/domain/markdown"
 	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/render"
+	"github.com/renatogalera/promptgen/internal/domain/resolver"
+	"github.com/renatogalera/promptgen/internal/domain/tokens"
 	"github.com/renatogalera/promptgen/internal/domain/xml"
+	"github.com/renatogalera/promptgen/internal/storage/analytics"
+	"github.com/renatogalera/promptgen/internal/storage/history"
+	"github.com/renatogalera/promptgen/internal/storage/state"
 	"github.com/renatogalera/promptgen/internal/storage/yaml"
 	"github.com/renatogalera/promptgen/internal/ui/keymap"
 	"github.com/renatogalera/promptgen/internal/ui/style"
@@ -22,17 +32,41 @@ type Application struct {
 	model Model
 }
 
-func NewApplication(promptFile string) *Application {
+func NewApplication(promptFile string, settings config.Settings, options Options) *Application {
 	promptService := prompt.NewService()
 	yamlRepo := yaml.NewRepository(promptFile, promptService)
-	xmlFormatter := xml.NewFormatter()
-	clipboardManager := clipboard.New()
+	stateStore := state.NewStore(config.StatePath())
+	statusMessage := ""
+	if err := stateStore.Load(); err != nil {
+		statusMessage = style.New().Error.Render(err.Error())
+	}
+	promptRenderer, err := NewRenderer(promptService, settings)
+	if err != nil {
+		statusMessage = style.New().Error.Render(err.Error())
+	}
+	clipboardManager, err := clipboard.NewForBackend(settings.Clipboard.Backend, config.ExpandHome(settings.Clipboard.File), settings.Clipboard.Command)
+	if err != nil {
+		statusMessage = style.New().Error.Render(err.Error())
+		clipboardManager, _ = clipboard.NewForBackend(clipboard.BackendAuto, config.ExpandHome(settings.Clipboard.File), settings.Clipboard.Command)
+	}
+	secretScanner, err := newSecretScanner(settings.Secrets)
+	if err != nil {
+		statusMessage = style.New().Error.Render(err.Error())
+	}
+	// An unknown model family is reported by NewRenderer.
+	family, err := tokens.ParseFamily(settings.Tokens.Model)
+	if err != nil {
+		family = tokens.Families[0]
+	}
 	s := spinner.New(
 		spinner.WithSpinner(spinner.Dot),
 		spinner.WithStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("205"))),
 	)
-	delegate := list.NewDefaultDelegate()
-	l := list.New(nil, delegate, 0, 0)
+	search := newSearchFilter(filter.FuzzyFilter)
+	if settings.Search.Mode == config.SearchSubstring {
+		search = newSearchFilter(filter.MultiTokenSubstringFilter)
+	}
+	l := list.New(nil, newSearchDelegate(search), 0, 0)
 	l.Title = "AI Prompts"
 	l.Styles.Title = style.New().Title
 
@@ -43,7 +77,7 @@ func NewApplication(promptFile string) *Application {
 	l.SetShowPagination(true)
 	l.SetShowTitle(true)
 
-	l.Filter = filter.MultiTokenSubstringFilter
+	l.Filter = search.Filter
 
 	vp := viewport.New(0, 0)
 	vp.Style = style.New().Viewport
@@ -51,9 +85,18 @@ func NewApplication(promptFile string) *Application {
 	h := help.New()
 	h.ShowAll = true
 
+	format := render.FormatXML
+	if options.Format != "" {
+		format = options.Format
+	}
+	keys := keymap.New()
+	if options.Print {
+		keys.Copy.SetHelp("c", "print and exit")
+	}
+
 	app := &Application{
 		model: Model{
-			keyMap:        keymap.New(),
+			keyMap:        keys,
 			help:          h,
 			list:          l,
 			spinner:       s,
@@ -64,15 +107,56 @@ func NewApplication(promptFile string) *Application {
 			variables:     make(map[string]string),
 			styles:        style.New(),
 			promptService: promptService,
+			resolver:      resolver.New(),
 			yamlRepo:      yamlRepo,
-			xmlFormatter:  xmlFormatter,
+			renderer:      promptRenderer,
+			format:        format,
 			clipboardMgr:  clipboardManager,
+			stateStore:    stateStore,
+			events:        analytics.NewLog(config.EventsPath()),
+			historyStore:  history.NewStore(config.HistoryPath()),
+			secrets:       secretScanner,
+			family:        family,
+			search:        search,
+			tags:          tagPane{selected: make(map[string]bool)},
+			folders:       folderPane{collapsed: make(map[string]bool)},
+			sortMode:      stateStore.SortMode(),
+			settings:      settings,
+			options:       options,
+			statusMessage: statusMessage,
 		},
 	}
 
 	return app
 }
 
+// NewRenderer returns a renderer set up from the settings: the trusted
+// sources, the policy for missing docs and the doc limit of prompts without
+// one of their own. A setting that does not parse keeps its default and is
+// reported in the error, so the interface can still start with the others.
+func NewRenderer(promptService *prompt.Service, settings config.Settings) (*render.Renderer, error) {
+	renderer := render.New(promptService, resolver.New(), xml.NewFormatter(), markdown.NewFormatter())
+	renderer.TrustSources(settings.TrustedSourcePaths()...)
+
+	var errs []error
+	if policy, err := docs.ParsePolicy(settings.Docs.Missing); err != nil {
+		errs = append(errs, err)
+	} else {
+		renderer.SetMissingDocs(policy)
+	}
+	family, err := tokens.ParseFamily(settings.Tokens.Model)
+	if err != nil {
+		errs = append(errs, err)
+		family = tokens.Families[0]
+	}
+	if limit, err := (docs.Limit{Family: family}).With(prompt.DocLimit(settings.Docs.Limit)); err != nil {
+		errs = append(errs, err)
+	} else {
+		renderer.SetDocLimit(limit)
+	}
+	return renderer, errors.Join(errs...)
+}
+
 func (a *Application) Init() tea.Cmd {
 	return a.model.Init()
 }
@@ -88,6 +172,12 @@ func (a *Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
+// Output returns the prompt delivered by the copy action in print mode, or
+// an empty string when the user quit without one.
+func (a *Application) Output() string {
+	return a.model.output
+}
+
 func (a *Application) View() string {
 	return a.model.View()
 }
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+func TestNewRendererAppliesDocSettings(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "guide.md"), []byte("0123456789\nabcdefghij\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.Settings{Docs: config.DocSettings{
+		Missing: "warn",
+		Limit:   config.DocLimitSettings{Max: 11},
+	}}
+
+	renderer, err := NewRenderer(prompt.NewService(), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := prompt.Prompt{
+		Title:  "Guide",
+		Docs:   []string{"guide.md", "missing.md"},
+		Source: filepath.Join(dir, "prompts.yaml"),
+	}
+	files, notes, err := renderer.PreviewDocs(p)
+	if err != nil {
+		t.Fatalf("PreviewDocs() error: %v (a missing doc should only warn)", err)
+	}
+	if len(files) != 1 || files[0].Content != "0123456789\n" {
+		t.Errorf("PreviewDocs() = %+v, want guide.md cut to 11 bytes", files)
+	}
+	if len(notes) != 2 {
+		t.Errorf("PreviewDocs() notes = %q, want the missing doc and the cut", notes)
+	}
+}
+
+func TestNewRendererReportsEverySetting(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings config.Settings
+		want     []string
+	}{
+		{name: "defaults"},
+		{
+			name:     "missing-doc policy",
+			settings: config.Settings{Docs: config.DocSettings{Missing: "maybe"}},
+			want:     []string{"missing-doc policy 'maybe'"},
+		},
+		{
+			name:     "model family",
+			settings: config.Settings{Tokens: config.TokenSettings{Model: "bard"}},
+			want:     []string{"model family 'bard'"},
+		},
+		{
+			name: "all of them",
+			settings: config.Settings{
+				Docs:   config.DocSettings{Missing: "maybe", Limit: config.DocLimitSettings{Unit: "lines"}},
+				Tokens: config.TokenSettings{Model: "bard"},
+			},
+			want: []string{"missing-doc policy 'maybe'", "model family 'bard'", "doc limit unit 'lines'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := NewRenderer(prompt.NewService(), tt.settings)
+			if renderer == nil {
+				t.Fatal("NewRenderer() returned no renderer")
+			}
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("NewRenderer() error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("NewRenderer() succeeded, want an error mentioning %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("NewRenderer() error = %v, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/resolver"
+)
+
+func TestLoadBuiltinsCmd(t *testing.T) {
+	t.Setenv("PROMPTGEN_TEST_TEAM", "platform")
+	m := &Model{resolver: resolver.New()}
+
+	m.selectedPrompt = prompt.Prompt{Title: "Plain", Content: "No built-ins here"}
+	if cmd := m.loadBuiltinsCmd(); cmd != nil {
+		t.Error("loadBuiltinsCmd() for a prompt without built-ins returned a command")
+	}
+
+	m.selectedPrompt = prompt.Prompt{ID: "team", Title: "Team", Content: "For {{{@env.PROMPTGEN_TEST_TEAM}}}"}
+	cmd := m.loadBuiltinsCmd()
+	if cmd == nil {
+		t.Fatal("loadBuiltinsCmd() returned no command")
+	}
+	// The command resolves the prompt selected when it was created.
+	m.selectedPrompt = prompt.Prompt{Title: "Plain"}
+
+	result := cmd()
+	msg, ok := result.(builtinsLoadedMsg)
+	if !ok {
+		t.Fatalf("command returned %T, want builtinsLoadedMsg", result)
+	}
+	want := builtinsLoadedMsg{key: "team", values: map[string]string{"@env.PROMPTGEN_TEST_TEAM": "platform"}}
+	if !reflect.DeepEqual(msg, want) {
+		t.Errorf("command returned %+v, want %+v", msg, want)
+	}
+
+	if cmd := m.setBuiltins(msg); cmd != nil || m.builtinValues != nil {
+		t.Error("setBuiltins() applied values resolved for another prompt")
+	}
+}
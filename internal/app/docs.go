@@ -0,0 +1,316 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/render"
+	"github.com/renatogalera/promptgen/internal/ui/keymap"
+	"github.com/renatogalera/promptgen/internal/ui/mdrender"
+)
+
+// docViewer shows the docs of the selected prompt one at a time, Markdown
+// rendered, with a search over the rendered lines.
+type docViewer struct {
+	files     []prompt.File
+	current   int
+	searching bool
+	input     textinput.Model
+	matches   []int
+	match     int
+}
+
+type docsLoadedMsg struct {
+	files []prompt.File
+	notes []string
+}
+
+// previewDocsMsg carries the docs of a prompt, cut to the doc limit, for the
+// preview and the token estimate.
+type previewDocsMsg struct {
+	key   string
+	files []prompt.File
+	err   error
+}
+
+func (m *Model) openDocsCmd() tea.Cmd {
+	p := m.selectedPrompt
+	if !p.HasDocs() {
+		m.statusMessage = m.styles.Error.Render("This prompt has no docs; attach them with doc: or docs: in the prompts file")
+		m.statusCmd = m.clearStatusCmd()
+		return m.statusCmd
+	}
+
+	return func() tea.Msg {
+		files, notes, err := m.renderer.Docs(p)
+		if err != nil {
+			return statusMsg{message: m.styles.Error.Render(err.Error())}
+		}
+		if len(files) == 0 {
+			return statusMsg{message: m.styles.Error.Render("None of the docs of this prompt could be read")}
+		}
+		return docsLoadedMsg{files: files, notes: notes}
+	}
+}
+
+// loadPreviewDocsCmd reads the docs of the selected prompt once, so the
+// preview does not read them on every keystroke.
+func (m *Model) loadPreviewDocsCmd() tea.Cmd {
+	p := m.selectedPrompt
+	if !p.HasDocs() {
+		return nil
+	}
+	return func() tea.Msg {
+		files, _, err := m.renderer.PreviewDocs(p)
+		return previewDocsMsg{key: p.Key(), files: files, err: err}
+	}
+}
+
+// setPreviewDocs attaches the loaded docs to the preview of the prompt they
+// were loaded for. A doc that cannot be read only leaves the preview without
+// docs; copying reports it again.
+func (m *Model) setPreviewDocs(msg previewDocsMsg) tea.Cmd {
+	if msg.key != m.selectedPrompt.Key() {
+		return nil
+	}
+	if msg.err != nil {
+		m.statusMessage = m.styles.Error.Render("Docs not previewed: " + msg.err.Error())
+		m.statusCmd = m.clearStatusCmd()
+		return m.statusCmd
+	}
+	m.previewDocs = msg.files
+	m.updateEstimate()
+	if m.state == config.StateVariableInput {
+		m.refreshPreview(false)
+	}
+	return nil
+}
+
+// previewPrompt is the selected prompt with its loaded docs attached.
+func (m Model) previewPrompt() prompt.Prompt {
+	p := m.selectedPrompt
+	p.DocFiles = m.previewDocs
+	return p
+}
+
+func (m *Model) showDocs(msg docsLoadedMsg) tea.Cmd {
+	m.docs = docViewer{files: msg.files}
+	m.state = config.StateDocView
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	m.viewport.GotoTop()
+
+	if len(msg.notes) == 0 {
+		return nil
+	}
+	m.statusMessage = m.styles.Error.Render(strings.Join(msg.notes, "; "))
+	m.statusCmd = m.clearStatusCmd()
+	return m.statusCmd
+}
+
+func (m *Model) closeDocView() {
+	m.docs = docViewer{}
+	m.state = config.StatePromptView
+	m.viewport.SetContent(m.selectedPrompt.Content)
+	m.viewport.GotoTop()
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+}
+
+func (m *Model) updateDocView(msg tea.KeyMsg) tea.Cmd {
+	if m.docs.searching {
+		return m.updateDocSearch(msg)
+	}
+
+	switch {
+	case keymap.Matches(msg, m.keyMap.Back):
+		m.closeDocView()
+		return nil
+	case keymap.Matches(msg, m.keyMap.Search):
+		m.docs.searching = true
+		m.docs.input = textinput.New()
+		m.docs.input.Prompt = "/"
+		m.docs.input.PromptStyle = m.styles.InputLabel
+		*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m.docs.input.Focus()
+	case keymap.Matches(msg, m.keyMap.NextMatch):
+		return m.jumpToMatch(1)
+	case keymap.Matches(msg, m.keyMap.PrevMatch):
+		return m.jumpToMatch(-1)
+	case keymap.Matches(msg, m.keyMap.Tab), keymap.Matches(msg, m.keyMap.ShiftTab):
+		step := 1
+		if keymap.Matches(msg, m.keyMap.ShiftTab) {
+			step = len(m.docs.files) - 1
+		}
+		m.docs.current = (m.docs.current + step) % len(m.docs.files)
+		m.docs.match = 0
+		*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		m.viewport.GotoTop()
+		return nil
+	case keymap.Matches(msg, m.keyMap.Copy):
+		m.sink = m.copySink()
+		return m.requestCopy(m.selectedPrompt, nil)
+	case keymap.Matches(msg, m.keyMap.CopyPrompt):
+		p := m.selectedPrompt
+		p.Doc, p.Docs = "", nil
+		m.sink = m.copySink()
+		return m.requestCopy(p, nil)
+	case keymap.Matches(msg, m.keyMap.CopyDoc):
+		return m.copyDocCmd()
+	case keymap.Matches(msg, m.keyMap.Override):
+		return m.overrideLimitCmd()
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return cmd
+}
+
+// updateDocSearch edits the search, jumping to the first match as the user
+// types. Enter keeps the search for n and N; Esc clears it.
+func (m *Model) updateDocSearch(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case keymap.Matches(msg, m.keyMap.Cancel):
+		m.docs.searching = false
+		m.docs.input.SetValue("")
+		*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return nil
+	case keymap.Matches(msg, m.keyMap.Confirm):
+		m.docs.searching = false
+		m.docs.input.Blur()
+		*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.docs.input, cmd = m.docs.input.Update(msg)
+	m.docs.match = 0
+	m.refreshDoc()
+	m.scrollToMatch()
+	return cmd
+}
+
+func (m *Model) jumpToMatch(step int) tea.Cmd {
+	count := len(m.docs.matches)
+	if count == 0 {
+		if m.docs.input.Value() == "" {
+			return nil
+		}
+		m.statusMessage = m.styles.Error.Render(fmt.Sprintf("No matches for '%s'", m.docs.input.Value()))
+		m.statusCmd = m.clearStatusCmd()
+		return m.statusCmd
+	}
+	m.docs.match = (m.docs.match + step + count) % count
+	m.refreshDoc()
+	m.scrollToMatch()
+	return nil
+}
+
+func (m *Model) scrollToMatch() {
+	if len(m.docs.matches) > 0 {
+		m.viewport.SetYOffset(m.docs.matches[m.docs.match] - m.viewport.Height/3)
+	}
+}
+
+// copyDocCmd copies the doc on screen by itself, screened like a rendered
+// prompt.
+func (m *Model) copyDocCmd() tea.Cmd {
+	doc := m.docs.files[m.docs.current]
+	d := delivery{prompt: m.selectedPrompt, format: m.format, target: m.copySink(), result: render.Result{Output: doc.Content}}
+	m.blockedCopy = nil
+	return func() tea.Msg {
+		return m.screenDelivery(d)
+	}
+}
+
+// refreshDoc renders the doc on screen at the viewport width, Markdown files
+// formatted, and highlights the lines matching the search.
+func (m *Model) refreshDoc() {
+	if len(m.docs.files) == 0 {
+		return
+	}
+	doc := m.docs.files[m.docs.current]
+	content := strings.TrimRight(doc.Content, "\n")
+	if mdrender.IsMarkdown(doc.Path) {
+		content = mdrender.Render(content, m.viewport.Width-4)
+	}
+
+	lines := strings.Split(content, "\n")
+	query := strings.ToLower(m.docs.input.Value())
+	m.docs.matches = nil
+	if query != "" {
+		for i, line := range lines {
+			plain := ansi.Strip(line)
+			if !strings.Contains(strings.ToLower(plain), query) {
+				continue
+			}
+			highlight := m.styles.Placeholder
+			if len(m.docs.matches) == m.docs.match {
+				highlight = m.styles.ActiveValue
+			}
+			m.docs.matches = append(m.docs.matches, i)
+			lines[i] = highlightQuery(plain, query, highlight)
+		}
+	}
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// highlightQuery styles every case-insensitive occurrence of query in line,
+// or the whole line when lowercasing changes its length.
+func highlightQuery(line, query string, highlight lipgloss.Style) string {
+	lower := strings.ToLower(line)
+	if len(lower) != len(line) {
+		return highlight.Render(line)
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, query)
+		if i < 0 {
+			break
+		}
+		b.WriteString(line[:i] + highlight.Render(line[i:i+len(query)]))
+		line, lower = line[i+len(query):], lower[i+len(query):]
+	}
+	b.WriteString(line)
+	return b.String()
+}
+
+func (m Model) renderDocHeader() string {
+	var view strings.Builder
+	view.WriteString(m.styles.Title.Render("Docs · "+m.selectedPrompt.Title) + "\n")
+	if len(m.docs.files) == 0 {
+		return view.String()
+	}
+
+	doc := m.docs.files[m.docs.current]
+	line := m.styles.InputLabel.Render(doc.Path)
+	if len(m.docs.files) > 1 {
+		line += lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("  %d of %d, tab for the next", m.docs.current+1, len(m.docs.files)))
+	}
+	view.WriteString(ansi.Truncate("  "+line, max(m.width-4, 1), "…") + "\n")
+
+	switch query := m.docs.input.Value(); {
+	case m.docs.searching:
+		view.WriteString("  " + m.docs.input.View() + "\n")
+	case query != "":
+		found := "no matches"
+		if len(m.docs.matches) > 0 {
+			found = fmt.Sprintf("match %d of %d", m.docs.match+1, len(m.docs.matches))
+		}
+		view.WriteString("  " + m.styles.InputLabel.Render("/") + query + lipgloss.NewStyle().Faint(true).Render("  "+found+", n/N to move") + "\n")
+	}
+
+	hint := "c to copy the prompt with its docs, p the prompt only, d this doc only, / to search, Esc to go back."
+	view.WriteString(ansi.Truncate("  "+lipgloss.NewStyle().Faint(true).Render(hint), max(m.width-4, 1), "…") + "\n")
+	return view.String()
+}
+
+func (m Model) renderDocView() string {
+	return m.renderDocHeader() + m.styles.Viewport.Render(m.viewport.View())
+}
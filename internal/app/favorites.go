@@ -0,0 +1,66 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+type favoriteToggledMsg struct {
+	title    string
+	favorite bool
+}
+
+// toggleFavoriteCmd stars or unstars the selected prompt in the library.
+func (m *Model) toggleFavoriteCmd() tea.Cmd {
+	item, ok := m.list.SelectedItem().(prompt.Item)
+	if !ok {
+		return nil
+	}
+	key, title, favorite := item.Key(), item.Prompt.Title, !item.Favorite
+
+	return func() tea.Msg {
+		err := m.yamlRepo.UpdatePrompt(key, func(p *prompt.Prompt) {
+			p.Favorite = favorite
+		})
+		if err != nil {
+			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("Error saving favorite: %v", err))}
+		}
+		return favoriteToggledMsg{title: title, favorite: favorite}
+	}
+}
+
+func (m *Model) handleFavoriteToggled(msg favoriteToggledMsg) tea.Cmd {
+	text := fmt.Sprintf("Added '%s' to favorites", msg.title)
+	if !msg.favorite {
+		text = fmt.Sprintf("Removed '%s' from favorites", msg.title)
+	}
+	m.statusMessage = m.styles.Success.Render(text)
+	m.statusCmd = m.clearStatusCmd()
+	return tea.Batch(m.statusCmd, m.loadPromptsCmd())
+}
+
+// cycleSortMode switches the list to the next order and remembers it.
+func (m *Model) cycleSortMode() tea.Cmd {
+	m.sortMode = m.sortMode.Next()
+	mode := m.sortMode
+	m.statusMessage = m.styles.Success.Render("Sorted by " + mode.Label())
+	m.statusCmd = m.clearStatusCmd()
+
+	save := func() tea.Msg {
+		if err := m.stateStore.SetSortMode(mode); err != nil {
+			return errMsg{err: err}
+		}
+		return nil
+	}
+	return tea.Batch(m.refreshListItems(), m.statusCmd, save)
+}
+
+// sortItems returns the list items in the current sort order.
+func (m Model) sortItems(items []prompt.Item) []prompt.Item {
+	sorted := append([]prompt.Item(nil), items...)
+	m.promptService.SortItems(sorted, m.sortMode, m.stateStore.Usage)
+	return sorted
+}
@@ -0,0 +1,243 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/ui/keymap"
+)
+
+// folderPane is the sidebar of the prompt list showing the category tree.
+// Opening a folder narrows the list to its prompts, subfolders included. A
+// prompt picked up with the move key is dropped on the folder chosen next.
+type folderPane struct {
+	visible     bool
+	focused     bool
+	cursor      int
+	collapsed   map[string]bool
+	current     string
+	moving      string
+	movingTitle string
+	naming      bool
+	input       textinput.Model
+}
+
+type folderRow struct {
+	node  *prompt.CategoryNode
+	depth int
+}
+
+type promptMovedMsg struct {
+	title, category string
+}
+
+func (m *Model) toggleFolderPane() {
+	m.folders.visible = !m.folders.visible
+	m.folders.focused = m.folders.visible
+	m.tags.visible, m.tags.focused = false, false
+	if !m.folders.visible {
+		m.cancelMove()
+	}
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+}
+
+// startMove picks up the selected prompt and opens the folder tree to drop
+// it on a folder.
+func (m *Model) startMove() {
+	item, ok := m.list.SelectedItem().(prompt.Item)
+	if !ok {
+		return
+	}
+	m.folders.moving = item.Key()
+	m.folders.movingTitle = item.Prompt.Title
+	if !m.folders.visible {
+		m.toggleFolderPane()
+	}
+	m.folders.focused = true
+}
+
+func (m *Model) cancelMove() {
+	m.folders.moving = ""
+	m.folders.movingTitle = ""
+	m.folders.naming = false
+}
+
+func (m Model) folderRows() []folderRow {
+	root := m.prompts.CategoryTree()
+	rows := []folderRow{{node: root}}
+
+	var walk func(n *prompt.CategoryNode, depth int)
+	walk = func(n *prompt.CategoryNode, depth int) {
+		for _, c := range n.Children {
+			rows = append(rows, folderRow{node: c, depth: depth})
+			if !m.folders.collapsed[c.Path] {
+				walk(c, depth+1)
+			}
+		}
+	}
+	walk(root, 1)
+	return rows
+}
+
+func (m *Model) updateFolderPane(msg tea.KeyMsg) tea.Cmd {
+	if m.folders.naming {
+		return m.updateFolderNaming(msg)
+	}
+
+	rows := m.folderRows()
+	m.folders.cursor = min(m.folders.cursor, len(rows)-1)
+	row := rows[m.folders.cursor]
+
+	switch {
+	case keymap.Matches(msg, m.keyMap.Folders):
+		m.toggleFolderPane()
+	case keymap.Matches(msg, m.keyMap.Back), keymap.Matches(msg, m.keyMap.Tab):
+		m.cancelMove()
+		m.folders.focused = false
+	case keymap.Matches(msg, m.keyMap.Up):
+		m.folders.cursor = max(m.folders.cursor-1, 0)
+	case keymap.Matches(msg, m.keyMap.Down):
+		m.folders.cursor = min(m.folders.cursor+1, len(rows)-1)
+	case keymap.Matches(msg, m.keyMap.ToggleTag):
+		if len(row.node.Children) > 0 && row.node.Path != "" {
+			m.folders.collapsed[row.node.Path] = !m.folders.collapsed[row.node.Path]
+		}
+	case keymap.Matches(msg, m.keyMap.Select):
+		if m.folders.moving != "" {
+			return m.movePromptCmd(row.node.Path)
+		}
+		m.folders.current = row.node.Path
+		return m.refreshListItems()
+	case keymap.Matches(msg, m.keyMap.Create) && m.folders.moving != "":
+		m.folders.naming = true
+		m.folders.input = textinput.New()
+		m.folders.input.Prompt = "┃ "
+		m.folders.input.PromptStyle = m.styles.InputLabel
+		m.folders.input.Placeholder = "folder/path"
+		m.folders.input.Width = sidebarWidth - 6
+		if row.node.Path != "" {
+			m.folders.input.SetValue(row.node.Path + prompt.CategorySeparator)
+		}
+		return m.folders.input.Focus()
+	}
+	return nil
+}
+
+func (m *Model) updateFolderNaming(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case keymap.Matches(msg, m.keyMap.Cancel):
+		m.folders.naming = false
+		return nil
+	case keymap.Matches(msg, m.keyMap.Confirm):
+		m.folders.naming = false
+		return m.movePromptCmd(prompt.CleanCategory(m.folders.input.Value()))
+	}
+
+	var cmd tea.Cmd
+	m.folders.input, cmd = m.folders.input.Update(msg)
+	return cmd
+}
+
+// movePromptCmd stores the folder as the category of the prompt being moved.
+func (m *Model) movePromptCmd(category string) tea.Cmd {
+	key, title := m.folders.moving, m.folders.movingTitle
+	m.cancelMove()
+
+	return func() tea.Msg {
+		err := m.yamlRepo.UpdatePrompt(key, func(p *prompt.Prompt) {
+			p.Category = category
+		})
+		if err != nil {
+			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("Error moving prompt: %v", err))}
+		}
+		return promptMovedMsg{title: title, category: category}
+	}
+}
+
+func (m *Model) handlePromptMoved(msg promptMovedMsg) tea.Cmd {
+	folder := msg.category
+	if folder == "" {
+		folder = "the top level"
+	}
+	m.statusMessage = m.styles.Success.Render(fmt.Sprintf("Moved '%s' to %s", msg.title, folder))
+	m.statusCmd = m.clearStatusCmd()
+	return tea.Batch(m.statusCmd, m.loadPromptsCmd())
+}
+
+func (m Model) folderExists(path string) bool {
+	if path == "" {
+		return true
+	}
+	for _, p := range m.prompts.Prompts {
+		if p.InCategory(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Model) breadcrumbs() string {
+	if m.folders.current == "" {
+		return ""
+	}
+	return strings.Join(strings.Split(m.folders.current, prompt.CategorySeparator), " › ")
+}
+
+func (m Model) renderFolderPane(height int) string {
+	paneStyle := m.styles.PromptList.Width(sidebarWidth - 2).Height(height - 2)
+	if m.folders.focused {
+		paneStyle = paneStyle.BorderForeground(lipgloss.Color("#7D56F4"))
+	}
+
+	var view strings.Builder
+	if m.folders.moving != "" {
+		title := ansi.Truncate(m.folders.movingTitle, sidebarWidth-12, "…")
+		view.WriteString(" " + m.styles.InputLabel.Render("Move ") + title + "\n")
+		view.WriteString(lipgloss.NewStyle().Faint(true).Render(" enter drop · n new folder") + "\n")
+	} else {
+		view.WriteString(" " + m.styles.InputLabel.Render("Folders") + "\n\n")
+	}
+
+	rows := m.folderRows()
+	visibleRows := max(height-paneStyle.GetVerticalFrameSize()-4, 1)
+	offset := max(m.folders.cursor-visibleRows+1, 0)
+
+	for i := offset; i < len(rows) && i < offset+visibleRows; i++ {
+		row := rows[i]
+		marker := "  "
+		if len(row.node.Children) > 0 && row.node.Path != "" {
+			marker = "▾ "
+			if m.folders.collapsed[row.node.Path] {
+				marker = "▸ "
+			}
+		}
+		name := row.node.Name
+		if row.node.Path == "" {
+			name = "All prompts"
+		}
+		count := fmt.Sprintf("%d", row.node.Count)
+		label := ansi.Truncate(strings.Repeat("  ", max(row.depth-1, 0))+marker+name, max(sidebarWidth-7-len(count), 1), "…")
+		line := label + strings.Repeat(" ", max(sidebarWidth-5-lipgloss.Width(label)-len(count), 1)) + count
+
+		if row.node.Path == m.folders.current {
+			line = lipgloss.NewStyle().Bold(true).Render(line)
+		}
+		if i == m.folders.cursor && m.folders.focused {
+			line = m.styles.InputLabel.Render("> ") + m.styles.InputLabel.Render(line)
+		} else {
+			line = "  " + line
+		}
+		view.WriteString(line + "\n")
+	}
+
+	if m.folders.naming {
+		view.WriteString("\n " + m.folders.input.View())
+	}
+	return paneStyle.Render(view.String())
+}
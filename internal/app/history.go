@@ -0,0 +1,203 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/render"
+	"github.com/renatogalera/promptgen/internal/storage/history"
+	"github.com/renatogalera/promptgen/internal/ui/keymap"
+)
+
+// historyRows is the number of entries listed above the output preview.
+const historyRows = 8
+
+// historyScreen lists the rendered prompts, newest first, with the output of
+// the entry under the cursor below.
+type historyScreen struct {
+	entries []history.Entry
+	cursor  int
+}
+
+type historyLoadedMsg struct{ entries []history.Entry }
+
+type historyDeletedMsg struct{ id string }
+
+// recordsHistory reports whether a render with these values may be kept.
+// Sensitive or history-excluded values end up in the output, so such renders
+// are not kept at all.
+func (m Model) recordsHistory(p prompt.Prompt, vars map[string]string) bool {
+	if m.settings.OutputHistory.Disabled {
+		return false
+	}
+	for name, value := range vars {
+		if value == "" {
+			continue
+		}
+		if v, ok := p.Variable(name); ok && v.Sensitive {
+			return false
+		}
+		if m.settings.VariableHistory.Excludes(name) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Model) loadHistoryCmd() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := m.historyStore.Load()
+		if err != nil {
+			return errMsg{err: err}
+		}
+		return historyLoadedMsg{entries: entries}
+	}
+}
+
+func (m *Model) showHistory(entries []history.Entry) {
+	m.history.entries = entries
+	m.history.cursor = min(m.history.cursor, max(len(entries)-1, 0))
+	m.state = config.StateHistory
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	m.previewHistoryEntry()
+}
+
+func (m *Model) previewHistoryEntry() {
+	content := ""
+	if m.settings.OutputHistory.Disabled {
+		content = "The history is off. Remove output_history.disabled from the config file to keep rendered prompts."
+	} else if len(m.history.entries) == 0 {
+		content = "Nothing rendered yet."
+	} else {
+		content = m.history.entries[m.history.cursor].Output
+	}
+	m.viewport.SetContent(content)
+	m.viewport.GotoTop()
+}
+
+func (m *Model) updateHistory(msg tea.KeyMsg) tea.Cmd {
+	entries := m.history.entries
+
+	switch {
+	case keymap.Matches(msg, m.keyMap.Back):
+		m.state = config.StatePromptList
+		m.viewport.SetContent("")
+		return nil
+	case len(entries) == 0:
+		return nil
+	case keymap.Matches(msg, m.keyMap.Up):
+		m.history.cursor = max(m.history.cursor-1, 0)
+		m.previewHistoryEntry()
+	case keymap.Matches(msg, m.keyMap.Down):
+		m.history.cursor = min(m.history.cursor+1, len(entries)-1)
+		m.previewHistoryEntry()
+	case keymap.Matches(msg, m.keyMap.Select), keymap.Matches(msg, m.keyMap.Copy):
+		return m.copyHistoryEntryCmd(entries[m.history.cursor])
+	case keymap.Matches(msg, m.keyMap.Reopen):
+		return m.reopenHistoryEntry(entries[m.history.cursor])
+	case keymap.Matches(msg, m.keyMap.Delete):
+		return m.deleteHistoryEntryCmd(entries[m.history.cursor].ID)
+	default:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// copyHistoryEntryCmd copies the output exactly as it was rendered.
+func (m *Model) copyHistoryEntryCmd(entry history.Entry) tea.Cmd {
+	return func() tea.Msg {
+		backend, err := m.clipboardMgr.Copy(entry.Output)
+		if err != nil {
+			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("Clipboard error: %v", err))}
+		}
+		return statusMsg{message: m.styles.Success.Render(copiedMessage(backend))}
+	}
+}
+
+// reopenHistoryEntry opens the variable form of the entry's prompt filled
+// with the values it was rendered with.
+func (m *Model) reopenHistoryEntry(entry history.Entry) tea.Cmd {
+	p, ok := m.prompts.Find(entry.Prompt)
+	if !ok {
+		m.statusMessage = m.styles.Error.Render(fmt.Sprintf("Prompt '%s' no longer exists", entry.Title))
+		m.statusCmd = m.clearStatusCmd()
+		return m.statusCmd
+	}
+	if format, err := render.ParseFormat(entry.Format); err == nil {
+		m.format = format
+	}
+
+	docsCmd := m.selectPrompt(p)
+	if len(m.formVariables()) == 0 {
+		return docsCmd
+	}
+	m.sink = m.copySink()
+	cmd := m.openVariableForm()
+	for i, label := range m.inputLabels {
+		if value, ok := entry.Variables[label]; ok {
+			m.setInputValue(i, value)
+		}
+	}
+	return tea.Batch(docsCmd, cmd)
+}
+
+func (m *Model) deleteHistoryEntryCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.historyStore.Delete(id); err != nil {
+			return errMsg{err: err}
+		}
+		return historyDeletedMsg{id: id}
+	}
+}
+
+func (m *Model) handleHistoryDeleted(msg historyDeletedMsg) tea.Cmd {
+	for i, e := range m.history.entries {
+		if e.ID == msg.id {
+			m.history.entries = append(m.history.entries[:i:i], m.history.entries[i+1:]...)
+			break
+		}
+	}
+	m.history.cursor = min(m.history.cursor, max(len(m.history.entries)-1, 0))
+	m.previewHistoryEntry()
+	m.statusMessage = m.styles.Success.Render("History entry deleted")
+	m.statusCmd = m.clearStatusCmd()
+	return m.statusCmd
+}
+
+func (m Model) renderHistoryHeader() string {
+	var view strings.Builder
+	view.WriteString(m.styles.Title.Render("History") + "\n")
+
+	entries := m.history.entries
+	offset := max(m.history.cursor-historyRows+1, 0)
+	for i := offset; i < len(entries) && i < offset+historyRows; i++ {
+		e := entries[i]
+		line := fmt.Sprintf("%s  %s  %s", e.Time.Local().Format("2006-01-02 15:04"), e.Title, render.Format(e.Format).Label())
+		if summary := presetSummary(prompt.Preset{Values: e.Variables}, 0); summary != "" {
+			line += "  " + lipgloss.NewStyle().Faint(true).Render(summary)
+		}
+		line = ansi.Truncate(line, max(m.width-4, 1), "…")
+		if i == m.history.cursor {
+			line = m.styles.InputLabel.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		view.WriteString(line + "\n")
+	}
+	if len(entries) > 0 {
+		view.WriteString(lipgloss.NewStyle().Faint(true).Render("Enter or c to copy again, e to edit the variables, x to delete, Esc to go back.") + "\n")
+	}
+	return view.String()
+}
+
+func (m Model) renderHistory() string {
+	return m.renderHistoryHeader() + m.styles.Viewport.Render(m.viewport.View())
+}
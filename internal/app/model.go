@@ -2,20 +2,35 @@ package app
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/filepicker"
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/docs"
 	"github.com/renatogalera/promptgen/internal/domain/prompt"
-	"github.com/renatogalera/promptgen/internal/domain/xml"
+	"github.com/renatogalera/promptgen/internal/domain/render"
+	"github.com/renatogalera/promptgen/internal/domain/resolver"
+	"github.com/renatogalera/promptgen/internal/domain/search"
+	"github.com/renatogalera/promptgen/internal/domain/secrets"
+	"github.com/renatogalera/promptgen/internal/domain/stats"
+	"github.com/renatogalera/promptgen/internal/domain/tokens"
+	"github.com/renatogalera/promptgen/internal/storage/analytics"
+	"github.com/renatogalera/promptgen/internal/storage/history"
+	"github.com/renatogalera/promptgen/internal/storage/state"
 	"github.com/renatogalera/promptgen/internal/storage/yaml"
 	"github.com/renatogalera/promptgen/internal/ui/keymap"
 	"github.com/renatogalera/promptgen/internal/ui/style"
@@ -28,22 +43,55 @@ type Model struct {
 	list           list.Model
 	spinner        spinner.Model
 	viewport       viewport.Model
+	preview        viewport.Model
+	filePicker     filepicker.Model
 	textInputs     []textinput.Model
 	styles         style.Styles
 	promptService  *prompt.Service
+	resolver       *resolver.Resolver
+	renderer       *render.Renderer
 	yamlRepo       *yaml.Repository
-	xmlFormatter   *xml.Formatter
 	clipboardMgr   *clipboard.Manager
+	stateStore     *state.Store
+	events         *analytics.Log
+	historyStore   *history.Store
+	secrets        *secrets.Scanner
+	pendingSecrets *secretsFoundMsg
+	blockedCopy    *delivery
+	family         tokens.Family
+	estimate       tokens.Count
+	previewCount   tokens.Count
+	history        historyScreen
+	docs           docViewer
+	search         *searchFilter
+	tags           tagPane
+	folders        folderPane
+	sortMode       prompt.SortMode
+	settings       config.Settings
+	options        Options
+	sink           sink
+	output         string
 	inputLabels    []string
+	optionalVars   map[string]bool
 	prompts        prompt.PromptCollection
 	selectedPrompt prompt.Prompt
 	state          config.AppState
+	format         render.Format
 	promptFile     string
 	statusMessage  string
 	statusCmd      tea.Cmd
+	queryError     bool
 	showHelp       bool
 	activeInput    int
 	variables      map[string]string
+	pasted         map[int]pastedValue
+	historyPos     map[int]int
+	pendingCopy    *pendingCopy
+	presetName     textinput.Model
+	namingPreset   bool
+	presetCursor   int
+	builtinValues  map[string]string
+	previewDocs    []prompt.File
 	width          int
 	height         int
 }
@@ -54,10 +102,33 @@ func (e errMsg) Error() string { return e.err.Error() }
 
 type promptsLoadedMsg struct {
 	prompts prompt.PromptCollection
-	items   []list.Item
+	index   *search.Index
 }
 
-type copyDoneMsg struct{}
+type copyDoneMsg struct {
+	notes   []string
+	message string
+}
+
+// printReadyMsg carries the output of the copy action in print mode.
+type printReadyMsg struct{ output string }
+type clipboardPastedMsg struct {
+	index int
+	text  string
+}
+
+// pendingCopy is a copy waiting for the user to approve the commands it runs.
+type pendingCopy struct {
+	prompt prompt.Prompt
+	vars   map[string]string
+}
+
+// pastedValue keeps the original text of a multi-line paste, which the
+// single-line text input would otherwise flatten.
+type pastedValue struct {
+	raw   string
+	shown string
+}
 type promptSavedMsg struct{}
 type statusMsg struct{ message string }
 type clearStatusMsg struct{}
@@ -94,23 +165,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch m.state {
 		case config.StatePromptList:
 
-			if m.list.FilterState() == list.Filtering {
+			if m.options.Pick {
+				cmds = append(cmds, m.updatePickList(msg))
+			} else if m.tags.focused {
+				cmds = append(cmds, m.updateTagPane(msg))
+			} else if m.folders.focused {
+				cmds = append(cmds, m.updateFolderPane(msg))
+			} else if keymap.Matches(msg, m.keyMap.FullText) {
+				cmds = append(cmds, m.toggleFullText())
+			} else if m.list.FilterState() == list.Filtering {
 				m.list, cmd = m.list.Update(msg)
 				cmds = append(cmds, cmd)
 			} else {
 
 				switch {
 				case keymap.Matches(msg, m.keyMap.Select):
-					selectedListItem := m.list.SelectedItem()
-					if selectedListItem != nil {
-						if item, ok := selectedListItem.(prompt.Item); ok {
-							m.selectedPrompt = item.Prompt
-							m.state = config.StatePromptView
-							m.viewport.SetContent(m.selectedPrompt.Content)
-							m.viewport.GotoTop()
-							m.help.ShowAll = false
-						}
+					if item, ok := m.list.SelectedItem().(prompt.Item); ok {
+						cmds = append(cmds, m.selectPrompt(item.Prompt))
 					}
+				case keymap.Matches(msg, m.keyMap.Tags):
+					m.toggleTagPane()
+				case keymap.Matches(msg, m.keyMap.Folders):
+					m.toggleFolderPane()
+				case keymap.Matches(msg, m.keyMap.Move):
+					m.startMove()
+				case keymap.Matches(msg, m.keyMap.Favorite):
+					cmds = append(cmds, m.toggleFavoriteCmd())
+				case keymap.Matches(msg, m.keyMap.Sort):
+					cmds = append(cmds, m.cycleSortMode())
+				case keymap.Matches(msg, m.keyMap.Stats):
+					cmds = append(cmds, m.loadStatsCmd())
+				case keymap.Matches(msg, m.keyMap.History):
+					cmds = append(cmds, m.loadHistoryCmd())
+				case keymap.Matches(msg, m.keyMap.Tab) && (m.tags.visible || m.folders.visible):
+					m.tags.focused = m.tags.visible
+					m.folders.focused = m.folders.visible
 				case keymap.Matches(msg, m.keyMap.Create):
 					m.state = config.StatePromptCreation
 					m.inputLabels = []string{"Title", "Tags (comma-sep)", "Description", "Content"}
@@ -133,29 +222,112 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case keymap.Matches(msg, m.keyMap.Back):
 				m.state = config.StatePromptList
 				m.selectedPrompt = prompt.Prompt{}
+				m.builtinValues = nil
+				m.previewDocs = nil
 				m.viewport.SetContent("")
 				m.help.ShowAll = true
 			case keymap.Matches(msg, m.keyMap.Copy):
-
-				cmd = m.copyToClipboardCmd(m.selectedPrompt)
+				m.sink = m.copySink()
+				cmd = m.requestCopy(m.selectedPrompt, nil)
 				cmds = append(cmds, cmd)
-			case keymap.Matches(msg, m.keyMap.Select) && len(m.selectedPrompt.Variables) > 0:
-				m.state = config.StateVariableInput
-				m.inputLabels = m.selectedPrompt.Variables
-				m.initTextInputs(len(m.inputLabels))
-				m.activeInput = 0
-				if len(m.textInputs) > 0 {
-					cmds = append(cmds, m.textInputs[m.activeInput].Focus())
+			case keymap.Matches(msg, m.keyMap.SaveFile):
+				m.sink = sinkFile
+				if len(m.formVariables()) > 0 {
+					cmds = append(cmds, m.openVariableForm())
+				} else {
+					cmds = append(cmds, m.requestCopy(m.selectedPrompt, nil))
 				}
-				m.help.ShowAll = true
+			case keymap.Matches(msg, m.keyMap.Format):
+				m.format = m.format.Next()
+				m.updateEstimate()
+			case keymap.Matches(msg, m.keyMap.Tokenizer):
+				m.cycleTokenizer()
+			case keymap.Matches(msg, m.keyMap.Docs):
+				cmds = append(cmds, m.openDocsCmd())
+			case keymap.Matches(msg, m.keyMap.Override):
+				cmds = append(cmds, m.overrideLimitCmd())
+			case keymap.Matches(msg, m.keyMap.Presets):
+				if len(m.selectedPrompt.Presets) == 0 {
+					m.statusMessage = m.styles.Error.Render("This prompt has no presets; save one from the variable form with ctrl+s")
+					m.statusCmd = m.clearStatusCmd()
+					cmds = append(cmds, m.statusCmd)
+				} else {
+					m.sink = m.copySink()
+					m.openPresetPicker()
+				}
+			case keymap.Matches(msg, m.keyMap.Select) && len(m.formVariables()) > 0:
+				m.sink = m.copySink()
+				cmds = append(cmds, m.openVariableForm())
 			default:
 
 				m.viewport, cmd = m.viewport.Update(msg)
 				cmds = append(cmds, cmd)
 			}
+		case config.StatePresetPicker:
+			cmds = append(cmds, m.updatePresetPicker(msg))
+		case config.StateStats:
+			cmds = append(cmds, m.updateStats(msg))
+		case config.StateHistory:
+			cmds = append(cmds, m.updateHistory(msg))
+		case config.StateSecretConfirm:
+			cmds = append(cmds, m.updateSecretConfirm(msg))
+		case config.StateDocView:
+			cmds = append(cmds, m.updateDocView(msg))
+		case config.StateCommandConfirm:
+
+			switch {
+			case keymap.Matches(msg, m.keyMap.Accept):
+				pending := m.pendingCopy
+				m.pendingCopy = nil
+				m.state = m.promptState()
+				m.renderer.ApproveCommands(pending.prompt)
+				cmds = append(cmds, m.copyOutputCmd(pending.prompt, pending.vars))
+			case keymap.Matches(msg, m.keyMap.Reject), keymap.Matches(msg, m.keyMap.Cancel):
+				m.pendingCopy = nil
+				m.state = m.promptState()
+				m.statusMessage = m.styles.Error.Render("Copy cancelled: commands were not approved")
+				m.statusCmd = m.clearStatusCmd()
+				cmds = append(cmds, m.statusCmd)
+			}
+		case config.StateFilePicker:
+
+			if keymap.Matches(msg, m.keyMap.Cancel) {
+				m.state = config.StateVariableInput
+				cmds = append(cmds, m.textInputs[m.activeInput].Focus())
+				break
+			}
+
+			m.filePicker, cmd = m.filePicker.Update(msg)
+			cmds = append(cmds, cmd)
+			if ok, path := m.filePicker.DidSelectFile(msg); ok {
+				m.textInputs[m.activeInput].SetValue(m.relativePath(path))
+				m.textInputs[m.activeInput].CursorEnd()
+				m.state = config.StateVariableInput
+				cmds = append(cmds, m.textInputs[m.activeInput].Focus())
+			}
 		case config.StateVariableInput, config.StatePromptCreation:
 
+			if m.state == config.StateVariableInput && m.namingPreset {
+				cmds = append(cmds, m.updatePresetNaming(msg))
+				break
+			}
+
 			switch {
+			case m.state == config.StateVariableInput && keymap.Matches(msg, m.keyMap.SavePreset):
+				cmds = append(cmds, m.startPresetNaming())
+			case m.state == config.StateVariableInput && keymap.Matches(msg, m.keyMap.PickFile) && m.activeVariable().IsFile():
+				m.textInputs[m.activeInput].Blur()
+				cmds = append(cmds, m.openFilePicker())
+			case m.state == config.StateVariableInput && keymap.Matches(msg, m.keyMap.Paste):
+				cmds = append(cmds, m.pasteClipboardCmd(m.activeInput))
+			case m.state == config.StateVariableInput &&
+				(keymap.Matches(msg, m.keyMap.PreviewUp) || keymap.Matches(msg, m.keyMap.PreviewDown)):
+				m.preview, cmd = m.preview.Update(msg)
+				cmds = append(cmds, cmd)
+			case m.state == config.StateVariableInput && keymap.Matches(msg, m.keyMap.HistoryNext):
+				m.cycleHistory(1)
+			case m.state == config.StateVariableInput && keymap.Matches(msg, m.keyMap.HistoryPrev):
+				m.cycleHistory(-1)
 			case keymap.Matches(msg, m.keyMap.Cancel):
 				if len(m.textInputs) > 0 && m.activeInput < len(m.textInputs) {
 					m.textInputs[m.activeInput].Blur()
@@ -165,8 +337,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.state = config.StatePromptList
 					m.help.ShowAll = true
 				} else {
-					m.state = config.StatePromptView
-					m.help.ShowAll = false
+					m.state = m.promptState()
+					m.help.ShowAll = m.options.Pick
 				}
 
 				m.textInputs = nil
@@ -210,15 +382,76 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.statusCmd)
 
 	case promptsLoadedMsg:
-		m.state = config.StatePromptList
+		if m.state == config.StateLoading {
+			m.state = config.StatePromptList
+			m.help.ShowAll = true
+		}
 		m.prompts = msg.prompts
-		m.list.SetItems(msg.items)
-		m.help.ShowAll = true
+		m.search.setLibrary(msg.prompts.Prompts, msg.index)
+		cmds = append(cmds, m.refreshListItems())
+		if m.selectedPrompt.Title != "" {
+			if p, ok := m.prompts.Find(m.selectedPrompt.Key()); ok {
+				m.selectedPrompt = p
+				m.updateEstimate()
+				cmds = append(cmds, m.loadBuiltinsCmd(), m.loadPreviewDocsCmd())
+			}
+		}
+
+	case secretsFoundMsg:
+		m.showSecrets(msg)
+
+	case tokenLimitMsg:
+		cmds = append(cmds, m.handleTokenLimit(msg))
+
+	case historyLoadedMsg:
+		m.showHistory(msg.entries)
+
+	case docsLoadedMsg:
+		cmds = append(cmds, m.showDocs(msg))
+
+	case previewDocsMsg:
+		cmds = append(cmds, m.setPreviewDocs(msg))
+
+	case builtinsLoadedMsg:
+		cmds = append(cmds, m.setBuiltins(msg))
+
+	case historyDeletedMsg:
+		cmds = append(cmds, m.handleHistoryDeleted(msg))
+
+	case tagRenamedMsg:
+		cmds = append(cmds, m.handleTagRenamed(msg))
+
+	case promptMovedMsg:
+		cmds = append(cmds, m.handlePromptMoved(msg))
+
+	case favoriteToggledMsg:
+		cmds = append(cmds, m.handleFavoriteToggled(msg))
+
+	case statsLoadedMsg:
+		m.showStats(msg.report)
+
+	case presetSavedMsg:
+		m.selectedPrompt.SetPreset(msg.preset)
+		m.statusMessage = m.styles.Success.Render(fmt.Sprintf("Preset '%s' saved!", msg.preset.Name))
+		m.statusCmd = m.clearStatusCmd()
+		cmds = append(cmds, m.statusCmd, m.loadPromptsCmd())
+
+	case clipboardPastedMsg:
+		if m.state == config.StateVariableInput && msg.index < len(m.textInputs) {
+			m.setInputValue(msg.index, msg.text)
+		}
+
+	case printReadyMsg:
+		m.output = msg.output
+		return m, tea.Quit
 
 	case copyDoneMsg:
-		m.statusMessage = m.styles.Success.Render("Copied to clipboard!")
+		m.statusMessage = m.styles.Success.Render(msg.message)
+		if len(msg.notes) > 0 {
+			m.statusMessage += " " + m.styles.Error.Render(strings.Join(msg.notes, "; "))
+		}
 		m.statusCmd = m.clearStatusCmd()
-		cmds = append(cmds, m.statusCmd)
+		cmds = append(cmds, m.statusCmd, m.refreshListItems())
 
 	case promptSavedMsg:
 		m.statusMessage = m.styles.Success.Render("Prompt saved!")
@@ -256,11 +489,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case config.StatePromptView:
 			m.viewport, cmd = m.viewport.Update(msg)
 			cmds = append(cmds, cmd)
+		case config.StateFilePicker:
+			m.filePicker, cmd = m.filePicker.Update(msg)
+			cmds = append(cmds, cmd)
 		case config.StatePromptList:
 			m.list, cmd = m.list.Update(msg)
 			cmds = append(cmds, cmd)
 		case config.StateVariableInput, config.StatePromptCreation:
 
+			if m.state == config.StateVariableInput {
+				m.preview, cmd = m.preview.Update(msg)
+				cmds = append(cmds, cmd)
+			}
 			for i := range m.textInputs {
 				m.textInputs[i], cmd = m.textInputs[i].Update(msg)
 				if cmd != nil {
@@ -270,6 +510,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if _, ok := msg.(tea.KeyMsg); ok && m.state == config.StatePromptList {
+		m.checkQuery()
+	}
+
+	if m.state == config.StateVariableInput {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			m.refreshPreview(!keymap.Matches(msg, m.keyMap.PreviewUp) && !keymap.Matches(msg, m.keyMap.PreviewDown))
+		case tea.WindowSizeMsg, clipboardPastedMsg:
+			m.refreshPreview(true)
+		}
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
@@ -284,12 +537,34 @@ func (m Model) View() string {
 		loadingStyle := lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).PaddingTop(m.height/2 - 1)
 		s.WriteString(loadingStyle.Render(fmt.Sprintf("%s Loading prompts...", m.spinner.View())))
 	case config.StatePromptList:
-		s.WriteString(m.styles.PromptList.Render(m.list.View()))
+		listView := m.styles.PromptList.Render(m.list.View())
+		if m.tags.visible {
+			listView = lipgloss.JoinHorizontal(lipgloss.Top, m.renderTagPane(lipgloss.Height(listView)), listView)
+		} else if m.folders.visible {
+			listView = lipgloss.JoinHorizontal(lipgloss.Top, m.renderFolderPane(lipgloss.Height(listView)), listView)
+		}
+		s.WriteString(listView)
 	case config.StatePromptView:
 		s.WriteString(m.renderPromptViewHeader())
 		s.WriteString(m.styles.Viewport.Render(m.viewport.View()))
-	case config.StateVariableInput, config.StatePromptCreation:
+	case config.StateVariableInput:
+		s.WriteString(m.renderVariableInput())
+	case config.StatePromptCreation:
 		s.WriteString(m.renderInputForm())
+	case config.StateFilePicker:
+		s.WriteString(m.renderFilePicker())
+	case config.StateCommandConfirm:
+		s.WriteString(m.renderCommandConfirm())
+	case config.StatePresetPicker:
+		s.WriteString(m.renderPresetPicker())
+	case config.StateStats:
+		s.WriteString(m.renderStats())
+	case config.StateHistory:
+		s.WriteString(m.renderHistory())
+	case config.StateSecretConfirm:
+		s.WriteString(m.renderSecretConfirm())
+	case config.StateDocView:
+		s.WriteString(m.renderDocView())
 	default:
 		s.WriteString(m.styles.Error.Render("Unknown application state!"))
 	}
@@ -318,21 +593,28 @@ func (m Model) updateWindowSize(msg tea.WindowSizeMsg) Model {
 	m.height = msg.Height
 	m.help.Width = msg.Width
 
-	titleHeight := lipgloss.Height(m.styles.Title.Render("PromptGen") + "\n\n")
-	statusHeight := lipgloss.Height(m.renderStatusLine())
-	helpHeight := lipgloss.Height(m.renderHelpView())
-	footerHeight := statusHeight + helpHeight + lipgloss.Height(m.styles.Footer.Render(""))
-
-	availableHeight := m.height - titleHeight - footerHeight
+	availableHeight := m.availableHeight()
 
 	listStyle := m.styles.PromptList
 	listVPadding := listStyle.GetVerticalPadding()
 	listHPadding := listStyle.GetHorizontalPadding()
-	m.list.SetSize(m.width-listHPadding, availableHeight-listVPadding)
+	listWidth := m.width - listHPadding
+	if m.tags.visible || m.folders.visible {
+		listWidth -= sidebarWidth
+	}
+	m.list.SetSize(listWidth, availableHeight-listVPadding)
 
 	viewHeaderHeight := 0
 	if m.state == config.StatePromptView {
 		viewHeaderHeight = lipgloss.Height(m.renderPromptViewHeader())
+	} else if m.state == config.StateStats {
+		viewHeaderHeight = 1
+	} else if m.state == config.StateHistory {
+		viewHeaderHeight = lipgloss.Height(m.renderHistoryHeader())
+	} else if m.state == config.StateSecretConfirm {
+		viewHeaderHeight = lipgloss.Height(m.renderSecretHeader())
+	} else if m.state == config.StateDocView {
+		viewHeaderHeight = lipgloss.Height(m.renderDocHeader())
 	}
 	viewportStyle := m.styles.Viewport
 	vpVPadding := viewportStyle.GetVerticalPadding()
@@ -340,8 +622,13 @@ func (m Model) updateWindowSize(msg tea.WindowSizeMsg) Model {
 
 	m.viewport.Width = m.width - vpHPadding
 	m.viewport.Height = availableHeight - viewHeaderHeight - vpVPadding
+	if m.state == config.StateDocView {
+		m.refreshDoc()
+	}
 
-	if len(m.textInputs) > 0 {
+	if m.state == config.StateVariableInput {
+		m.resizeVariableForm()
+	} else if len(m.textInputs) > 0 {
 
 		formStyle := m.styles.Doc
 		formHPadding := formStyle.GetHorizontalPadding()
@@ -360,17 +647,31 @@ func (m Model) updateWindowSize(msg tea.WindowSizeMsg) Model {
 	return m
 }
 
+// availableHeight is the height left between the title and the footer.
+func (m Model) availableHeight() int {
+	titleHeight := lipgloss.Height(m.styles.Title.Render("PromptGen") + "\n\n")
+	statusHeight := lipgloss.Height(m.renderStatusLine())
+	helpHeight := lipgloss.Height(m.renderHelpView())
+	footerHeight := statusHeight + helpHeight + lipgloss.Height(m.styles.Footer.Render(""))
+
+	return m.height - titleHeight - footerHeight
+}
+
 func (m Model) renderStatusLine() string {
 
 	return m.styles.StatusLine.Render(m.statusMessage)
 }
 
 func (m Model) renderHelpView() string {
+	var keys help.KeyMap = m.keyMap
+	if m.options.Pick {
+		keys = pickHelp{m.keyMap}
+	}
 	if m.showHelp {
-		return m.help.View(m.keyMap)
+		return m.help.View(keys)
 	}
 
-	return m.help.ShortHelpView(m.keyMap.ShortHelp())
+	return m.help.ShortHelpView(keys.ShortHelp())
 }
 
 func (m Model) renderPromptViewHeader() string {
@@ -386,11 +687,101 @@ func (m Model) renderPromptViewHeader() string {
 	if m.selectedPrompt.Description != "" {
 		header.WriteString(m.styles.Info.Render(m.selectedPrompt.Description) + "\n")
 	}
+	header.WriteString(m.renderInheritance())
+	header.WriteString(m.renderBuiltinPreview())
+	if err := m.promptService.ValidateTemplate(m.selectedPrompt.Content); err != nil {
+		header.WriteString(m.styles.Error.Render(err.Error()) + "\n")
+	}
+
+	header.WriteString(m.styles.InputLabel.Render("Format: ") + m.format.Label() + "  " + m.renderTokenCount(m.estimate) + "\n")
 
 	header.WriteString("\n" + m.styles.ContentHeader.Render("Content:") + "\n")
 	return header.String()
 }
 
+func (m Model) renderInheritance() string {
+	var lines strings.Builder
+	if m.selectedPrompt.Extends != "" {
+		lines.WriteString(m.styles.InputLabel.Render("Extends: ") + m.selectedPrompt.Extends + "\n")
+	}
+	children := m.prompts.Children(m.selectedPrompt)
+	if len(children) > 0 {
+		titles := make([]string, len(children))
+		for i, c := range children {
+			titles[i] = c.Title
+		}
+		lines.WriteString(m.styles.InputLabel.Render("Children: ") + strings.Join(titles, ", ") + "\n")
+	}
+	return lines.String()
+}
+
+// builtinsLoadedMsg carries the values of the built-ins a prompt uses.
+type builtinsLoadedMsg struct {
+	key    string
+	values map[string]string
+	err    error
+}
+
+// loadBuiltinsCmd resolves the built-ins of the selected prompt off the
+// update loop, since @git ones run git.
+func (m *Model) loadBuiltinsCmd() tea.Cmd {
+	p := m.selectedPrompt
+	if len(m.resolver.Names(p.Content)) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		values, err := m.resolver.Values(p.Content)
+		return builtinsLoadedMsg{key: p.Key(), values: values, err: err}
+	}
+}
+
+// setBuiltins shows the resolved built-ins of the prompt they were resolved
+// for and counts them in the preview.
+func (m *Model) setBuiltins(msg builtinsLoadedMsg) tea.Cmd {
+	if msg.key != m.selectedPrompt.Key() {
+		return nil
+	}
+	if msg.err != nil {
+		m.statusMessage = m.styles.Error.Render("Built-ins not resolved: " + msg.err.Error())
+		m.statusCmd = m.clearStatusCmd()
+		return m.statusCmd
+	}
+	m.builtinValues = msg.values
+	m.updateEstimate()
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	if m.state == config.StateVariableInput {
+		m.refreshPreview(false)
+	}
+	return nil
+}
+
+func (m Model) renderBuiltinPreview() string {
+	if len(m.builtinValues) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(m.builtinValues))
+	for name := range m.builtinValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines strings.Builder
+	lines.WriteString(m.styles.InputLabel.Render("Built-ins:") + "\n")
+	for _, name := range names {
+		value := m.builtinValues[name]
+		if n := strings.Count(value, "\n"); n > 0 {
+			value = fmt.Sprintf("%s … (%d lines)", value[:strings.IndexByte(value, '\n')], n+1)
+		}
+		line := fmt.Sprintf("  %s = %s", name, value)
+		if m.width > 4 {
+			line = ansi.Truncate(line, m.width-4, "…")
+		}
+		lines.WriteString(lipgloss.NewStyle().Faint(true).Render(line) + "\n")
+	}
+	return lines.String()
+}
+
 func (m Model) renderInputForm() string {
 	var form strings.Builder
 	viewTitle := "New Prompt"
@@ -404,20 +795,74 @@ func (m Model) renderInputForm() string {
 		if i < len(m.inputLabels) {
 			label = m.inputLabels[i]
 		}
-		form.WriteString(m.styles.InputLabel.Render(label+":") + "\n")
+		form.WriteString(m.styles.InputLabel.Render(label + ":"))
+		if m.state == config.StateVariableInput {
+			if pasted, ok := m.pasted[i]; ok && pasted.shown == m.textInputs[i].Value() {
+				form.WriteString(" " + lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(%d lines)", strings.Count(pasted.raw, "\n")+1)))
+			}
+			if n := len(m.variableHistory(label)); n > 1 {
+				form.WriteString(" " + lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(%d remembered, ctrl+n/ctrl+p)", n)))
+			}
+			if v, ok := m.selectedPrompt.Variable(label); ok && v.IsFile() {
+				form.WriteString(" " + lipgloss.NewStyle().Faint(true).Render("(file, ctrl+o to browse)"))
+			}
+			if m.optionalVars[label] {
+				form.WriteString(" " + lipgloss.NewStyle().Faint(true).Render("(optional)"))
+			}
+		}
+		form.WriteString("\n")
 
 		form.WriteString(m.styles.InputView.Render(m.textInputs[i].View()) + "\n\n")
 	}
 
-	form.WriteString(
-		lipgloss.NewStyle().Faint(true).Render(
-			"Use Tab/Shift+Tab or Up/Down to navigate, Enter to confirm, Esc to cancel.",
-		),
-	)
+	if m.namingPreset {
+		form.WriteString(m.styles.InputLabel.Render("Save values as preset:") + "\n")
+		form.WriteString(m.styles.InputView.Render(m.presetName.View()) + "\n\n")
+		form.WriteString(lipgloss.NewStyle().Faint(true).Render("Enter to save the preset, Esc to cancel."))
+		return m.styles.Doc.Render(form.String())
+	}
+
+	hint := "Use Tab/Shift+Tab or Up/Down to navigate, Enter to confirm, Esc to cancel."
+	if m.state == config.StateVariableInput {
+		hint += " Ctrl+V pastes the clipboard into the field, Ctrl+N/Ctrl+P cycle remembered values and → accepts a suggestion. Ctrl+S saves the values as a preset."
+	}
+	form.WriteString(lipgloss.NewStyle().Faint(true).Render(hint))
 
 	return m.styles.Doc.Render(form.String())
 }
 
+func (m Model) renderFilePicker() string {
+	var view strings.Builder
+	view.WriteString(m.styles.Title.Render(fmt.Sprintf("Select file for: %s", m.activeVariable().Name)) + "\n\n")
+	view.WriteString(m.styles.Info.Render(m.filePicker.CurrentDirectory) + "\n\n")
+	view.WriteString(m.filePicker.View() + "\n")
+	view.WriteString(lipgloss.NewStyle().Faint(true).Render("Enter to select or open, h/← to go up, Esc to cancel."))
+	return m.styles.Doc.Render(view.String())
+}
+
+func (m Model) renderCommandConfirm() string {
+	var view strings.Builder
+	view.WriteString(m.styles.Title.Render("Run commands?") + "\n\n")
+	if m.pendingCopy == nil {
+		return m.styles.Doc.Render(view.String())
+	}
+
+	p := m.pendingCopy.prompt
+	view.WriteString(fmt.Sprintf("%s comes from a source that is not trusted:\n", m.styles.InputLabel.Render(p.Title)))
+	view.WriteString(m.styles.Info.Render(p.Source) + "\n\n")
+	view.WriteString("Rendering it runs the following commands:\n\n")
+	for _, v := range render.Commands(p) {
+		line := "  $ " + v.Command
+		if v.Dir != "" {
+			line += lipgloss.NewStyle().Faint(true).Render("  (in " + v.Dir + ")")
+		}
+		view.WriteString(m.styles.Error.Render(line) + "\n")
+	}
+	view.WriteString("\n" + lipgloss.NewStyle().Faint(true).Render(
+		"Press y to run them and copy, n or Esc to cancel. Add the file to trusted_sources in the config file to skip this question."))
+	return m.styles.Doc.Render(view.String())
+}
+
 func (m *Model) initTextInputs(count int) {
 	m.textInputs = make([]textinput.Model, count)
 
@@ -471,20 +916,31 @@ func (m *Model) handleInputFocus(msg tea.KeyMsg) tea.Cmd {
 	return tea.Batch(focusCmds...)
 }
 
+// selectPrompt opens the prompt view of p.
+func (m *Model) selectPrompt(p prompt.Prompt) tea.Cmd {
+	m.selectedPrompt = p
+	m.previewDocs = nil
+	m.builtinValues = nil
+	m.state = config.StatePromptView
+	m.viewport.SetContent(p.Content)
+	m.viewport.GotoTop()
+	m.help.ShowAll = false
+	m.updateEstimate()
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	return tea.Batch(m.loadBuiltinsCmd(), m.loadPreviewDocsCmd())
+}
+
 func (m *Model) handleVariableInputConfirm() tea.Cmd {
 
 	m.variables = make(map[string]string)
 	for i, label := range m.inputLabels {
 		if i < len(m.textInputs) {
-			m.variables[label] = m.textInputs[i].Value()
+			m.variables[label] = m.inputValue(i)
 		}
 	}
 
-	tempPrompt := m.selectedPrompt
-	tempPrompt.Content = m.promptService.ReplaceVariables(tempPrompt.Content, m.variables)
-
-	m.state = config.StatePromptView
-	m.help.ShowAll = false
+	m.state = m.promptState()
+	m.help.ShowAll = m.options.Pick
 
 	if len(m.textInputs) > 0 && m.activeInput < len(m.textInputs) {
 		m.textInputs[m.activeInput].Blur()
@@ -492,28 +948,299 @@ func (m *Model) handleVariableInputConfirm() tea.Cmd {
 	m.textInputs = nil
 	m.inputLabels = nil
 
-	return m.copyToClipboardCmd(tempPrompt)
+	return tea.Batch(
+		m.rememberVariablesCmd(m.selectedPrompt, m.variables),
+		m.requestCopy(m.selectedPrompt, m.variables),
+	)
+}
+
+func (m *Model) openVariableForm() tea.Cmd {
+	var cmds []tea.Cmd
+
+	m.state = config.StateVariableInput
+	m.inputLabels = m.formVariables()
+	m.optionalVars = m.promptService.OptionalVariables(m.selectedPrompt.Content)
+	m.initTextInputs(len(m.inputLabels))
+	m.pasted = make(map[int]pastedValue)
+	m.historyPos = make(map[int]int)
+
+	for i, label := range m.inputLabels {
+		v, _ := m.selectedPrompt.Variable(label)
+		if v.IsFile() {
+			m.textInputs[i].Placeholder = "path or glob"
+		} else if m.optionalVars[label] {
+			m.textInputs[i].Placeholder = "optional"
+		}
+		if v.Sensitive {
+			m.textInputs[i].EchoMode = textinput.EchoPassword
+		}
+		if history := m.variableHistory(label); len(history) > 0 {
+			m.setInputValue(i, history[0])
+			m.textInputs[i].ShowSuggestions = true
+			m.textInputs[i].SetSuggestions(history)
+			m.textInputs[i].KeyMap.AcceptSuggestion = key.NewBinding(key.WithKeys("right"))
+		}
+		if v.FromClipboard() {
+			cmds = append(cmds, m.pasteClipboardCmd(i))
+		}
+	}
+
+	m.activeInput = 0
+	if len(m.textInputs) > 0 {
+		cmds = append(cmds, m.textInputs[m.activeInput].Focus())
+	}
+	m.help.ShowAll = true
+	m.resizeVariableForm()
+
+	return tea.Batch(cmds...)
+}
+
+// setInputValue fills an input, keeping the line breaks of multi-line text
+// aside so they survive the single-line input.
+func (m *Model) setInputValue(i int, text string) {
+	m.textInputs[i].SetValue(text)
+	m.textInputs[i].CursorEnd()
+	if strings.Contains(text, "\n") {
+		m.pasted[i] = pastedValue{raw: text, shown: m.textInputs[i].Value()}
+	} else {
+		delete(m.pasted, i)
+	}
+}
+
+func (m *Model) cycleHistory(step int) {
+	history := m.variableHistory(m.inputLabels[m.activeInput])
+	if len(history) == 0 {
+		return
+	}
+	pos := (m.historyPos[m.activeInput] + step + len(history)) % len(history)
+	m.historyPos[m.activeInput] = pos
+	m.setInputValue(m.activeInput, history[pos])
+}
+
+// variableHistory returns the remembered values of a variable of the
+// selected prompt, unless it must not be remembered.
+func (m Model) variableHistory(name string) []string {
+	if !m.remembers(name) {
+		return nil
+	}
+	return m.stateStore.VariableHistory(m.selectedPrompt.Key(), name)
+}
+
+func (m Model) remembers(name string) bool {
+	if v, ok := m.selectedPrompt.Variable(name); ok && v.Sensitive {
+		return false
+	}
+	return m.settings.VariableHistory.Remembers(name)
+}
+
+func (m *Model) rememberVariablesCmd(p prompt.Prompt, vars map[string]string) tea.Cmd {
+	values := make(map[string]string, len(vars))
+	for name, value := range vars {
+		if m.remembers(name) {
+			values[name] = value
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		if err := m.stateStore.RememberVariables(p.Key(), values, m.settings.VariableHistory.MaxValues()); err != nil {
+			return errMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// formVariables returns the selected prompt's variables the user has to
+// fill in, leaving out the built-ins resolved at render time.
+func (m Model) formVariables() []string {
+	var names []string
+	for _, v := range m.selectedPrompt.Variables {
+		if !resolver.IsBuiltin(v.Name) && !v.IsCommand() {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
+
+// inputValue returns the value of an input, restoring the line breaks of a
+// multi-line paste as long as the user has not edited it since.
+func (m Model) inputValue(i int) string {
+	value := m.textInputs[i].Value()
+	if pasted, ok := m.pasted[i]; ok && pasted.shown == value {
+		return pasted.raw
+	}
+	return value
+}
+
+func (m Model) activeVariable() prompt.Variable {
+	if m.activeInput < 0 || m.activeInput >= len(m.inputLabels) {
+		return prompt.Variable{}
+	}
+	v, _ := m.selectedPrompt.Variable(m.inputLabels[m.activeInput])
+	return v
+}
+
+func (m *Model) openFilePicker() tea.Cmd {
+	fp := filepicker.New()
+	fp.CurrentDirectory, _ = os.Getwd()
+	fp.AutoHeight = false
+	fp.Height = max(m.height-12, 5)
+	fp.KeyMap.Back = key.NewBinding(key.WithKeys("h", "backspace", "left"), key.WithHelp("h", "back"))
+	m.filePicker = fp
+	m.state = config.StateFilePicker
+	return m.filePicker.Init()
+}
+
+func (m Model) relativePath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
 }
 
-func (m *Model) copyToClipboardCmd(p prompt.Prompt) tea.Cmd {
+// requestCopy copies the rendered prompt, first asking for confirmation when
+// it would run commands from a source the user has not trusted.
+func (m *Model) requestCopy(p prompt.Prompt, vars map[string]string) tea.Cmd {
+	m.blockedCopy = nil
+	if m.renderer.NeedsApproval(p) {
+		m.pendingCopy = &pendingCopy{prompt: p, vars: vars}
+		m.state = config.StateCommandConfirm
+		return nil
+	}
+	return m.copyOutputCmd(p, vars)
+}
+
+// delivery is a rendered prompt on its way to a sink.
+type delivery struct {
+	prompt prompt.Prompt
+	vars   map[string]string
+	format render.Format
+	target sink
+	record bool
+	result render.Result
+	// overLimit is set once the user chose to copy past the token limit.
+	overLimit bool
+}
+
+// copyOutputCmd renders the prompt and delivers it to the current sink: the
+// clipboard, a file in the output directory or, in print mode, stdout. When
+// the output contains secrets the user is asked first.
+func (m *Model) copyOutputCmd(p prompt.Prompt, vars map[string]string) tea.Cmd {
+	d := delivery{prompt: p, vars: vars, format: m.format, target: m.sink, record: m.recordsHistory(p, vars)}
 	return func() tea.Msg {
 
-		xmlOutput, err := m.xmlFormatter.FormatWithDoc(p)
+		result, err := m.renderer.Render(p, vars, d.format)
 		if err != nil {
+			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("%s generation error: %v", d.format.Label(), err))}
+		}
+		d.result = result
+		return m.screenDelivery(d)
+	}
+}
 
-			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("XML Generation error: %v", err))}
+// screenDelivery holds back output over the token limit or containing
+// secrets for the user to decide, and delivers anything else.
+func (m *Model) screenDelivery(d delivery) tea.Msg {
+	if !d.overLimit {
+		if count := tokens.Estimate(d.result.Output, m.family); m.overLimit(count) {
+			return tokenLimitMsg{delivery: d, count: count}
+		}
+	}
+	if m.secrets != nil {
+		if matches := m.secrets.Scan(d.result.Output); len(matches) > 0 {
+			return secretsFoundMsg{delivery: d, matches: matches}
 		}
+	}
+	return m.deliver(d)
+}
+
+func (m *Model) deliverCmd(d delivery) tea.Cmd {
+	return func() tea.Msg {
+		return m.deliver(d)
+	}
+}
+
+func (m *Model) deliver(d delivery) tea.Msg {
+	p, result := d.prompt, d.result
 
-		if err := m.clipboardMgr.Copy(xmlOutput); err != nil {
+	var message string
+	eventType := stats.EventCopied
+	switch d.target {
+	case sinkFile:
+		path, err := writeOutputFile(m.settings.Output.Directory(), p, d.format, result.Output, time.Now())
+		if err != nil {
+			return statusMsg{message: m.styles.Error.Render(err.Error())}
+		}
+		message = fmt.Sprintf("Saved to %s", path)
+		eventType = stats.EventRendered
+	case sinkStdout:
+		eventType = stats.EventRendered
+	default:
+		backend, err := m.clipboardMgr.Copy(result.Output)
+		if err != nil {
 
 			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("Clipboard error: %v", err))}
 		}
+		message = copiedMessage(backend)
+	}
 
-		return copyDoneMsg{}
+	if err := m.stateStore.RecordUse(p.Key(), time.Now()); err != nil {
+		result.Notes = append(result.Notes, err.Error())
+	}
+	if m.settings.Analytics.Enabled {
+		event := stats.Event{Time: time.Now(), Type: eventType, Prompt: p.Key(), Format: string(d.format), Variables: len(d.vars)}
+		if err := m.events.Append(event); err != nil {
+			result.Notes = append(result.Notes, err.Error())
+		}
+	}
+
+	if d.record {
+		entry := history.Entry{Time: time.Now(), Prompt: p.Key(), Title: p.Title, Format: string(d.format), Variables: d.vars, Output: result.Output}
+		if _, err := m.historyStore.Add(entry, m.settings.OutputHistory.MaxEntries()); err != nil {
+			result.Notes = append(result.Notes, err.Error())
+		}
+	}
+
+	if d.target == sinkStdout {
+		return printReadyMsg{output: result.Output}
+	}
+	return copyDoneMsg{notes: result.Notes, message: message}
+}
+
+// copiedMessage tells the user where the copied text went.
+func copiedMessage(backend clipboard.Backend) string {
+	switch b := backend.(type) {
+	case *clipboard.OSC52:
+		if b.Passthrough != clipboard.PassthroughNone {
+			return fmt.Sprintf("Sent to the terminal clipboard via OSC 52 (%s passthrough); your terminal may not support it", b.Passthrough)
+		}
+		return "Sent to the terminal clipboard via OSC 52; your terminal may not support it"
+	case *clipboard.File:
+		return fmt.Sprintf("No clipboard available; saved to %s", b.FilePath())
+	case *clipboard.Command:
+		return fmt.Sprintf("Copied with '%s'!", b.Command)
+	}
+	return "Copied to the system clipboard!"
+}
+
+func (m *Model) pasteClipboardCmd(index int) tea.Cmd {
+	return func() tea.Msg {
+		text, err := m.clipboardMgr.Paste()
+		if err != nil {
+			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("Clipboard error: %v", err))}
+		}
+		return clipboardPastedMsg{index: index, text: strings.TrimRight(text, "\n")}
 	}
 }
 
 func (m *Model) saveNewPromptCmd() tea.Cmd {
+	category := m.folders.current
 	return func() tea.Msg {
 
 		title := ""
@@ -539,6 +1266,9 @@ func (m *Model) saveNewPromptCmd() tea.Cmd {
 		if strings.TrimSpace(content) == "" {
 			return statusMsg{message: m.styles.Error.Render("Content cannot be empty")}
 		}
+		if err := m.promptService.ValidateTemplate(content); err != nil {
+			return statusMsg{message: m.styles.Error.Render(err.Error())}
+		}
 
 		newPrompt := m.promptService.CreatePrompt(
 			title,
@@ -546,6 +1276,7 @@ func (m *Model) saveNewPromptCmd() tea.Cmd {
 			description,
 			content,
 		)
+		newPrompt.Category = category
 
 		if err := m.yamlRepo.SavePrompt(newPrompt); err != nil {
 			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("Error saving prompt: %v", err))}
@@ -564,9 +1295,7 @@ func (m *Model) loadPromptsCmd() tea.Cmd {
 			return errMsg{err: fmt.Errorf("failed to load prompts from %s: %w", m.promptFile, err)}
 		}
 
-		items := collection.ToItems()
-
-		return promptsLoadedMsg{prompts: collection, items: items}
+		return promptsLoadedMsg{prompts: collection, index: search.Build(collection.Prompts, docs.Text)}
 	}
 }
 
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/ui/keymap"
+)
+
+// pickHelp lists the few keys the picker answers to.
+type pickHelp struct {
+	keymap.KeyMap
+}
+
+func (k pickHelp) ShortHelp() []key.Binding {
+	return []key.Binding{k.Search, k.Select, k.Cancel}
+}
+
+func (k pickHelp) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Search, k.FullText},
+		{k.Select, k.Cancel, k.Help},
+	}
+}
+
+// updatePickList handles the list of the picker: enter renders the selected
+// prompt, through the variable form when it has variables, and esc quits
+// without output.
+func (m *Model) updatePickList(msg tea.KeyMsg) tea.Cmd {
+	if keymap.Matches(msg, m.keyMap.FullText) {
+		return m.toggleFullText()
+	}
+
+	switch {
+	case m.list.FilterState() == list.Filtering:
+	case keymap.Matches(msg, m.keyMap.Cancel) && m.list.FilterState() == list.Unfiltered:
+		return tea.Quit
+	case keymap.Matches(msg, m.keyMap.Override) && m.blockedCopy != nil:
+		return m.overrideLimitCmd()
+	case keymap.Matches(msg, m.keyMap.Select):
+		item, ok := m.list.SelectedItem().(prompt.Item)
+		if !ok {
+			return nil
+		}
+		m.selectedPrompt = item.Prompt
+		m.previewDocs = nil
+		m.builtinValues = nil
+		m.sink = sinkStdout
+		if len(m.formVariables()) > 0 {
+			return tea.Batch(m.openVariableForm(), m.loadBuiltinsCmd(), m.loadPreviewDocsCmd())
+		}
+		return m.requestCopy(m.selectedPrompt, nil)
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return cmd
+}
+
+// promptState is the state shown once the variable form or a copy is done:
+// the prompt view, or the list in the picker, which has no prompt view.
+func (m Model) promptState() config.AppState {
+	if m.options.Pick {
+		return config.StatePromptList
+	}
+	return config.StatePromptView
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/ui/keymap"
+)
+
+type presetSavedMsg struct{ preset prompt.Preset }
+
+func (m *Model) startPresetNaming() tea.Cmd {
+	m.textInputs[m.activeInput].Blur()
+	m.namingPreset = true
+
+	m.presetName = textinput.New()
+	m.presetName.Prompt = "┃ "
+	m.presetName.PromptStyle = m.styles.InputLabel
+	m.presetName.Placeholder = "preset name"
+	m.presetName.Width = max(m.textInputs[m.activeInput].Width, 20)
+	return m.presetName.Focus()
+}
+
+func (m *Model) updatePresetNaming(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case keymap.Matches(msg, m.keyMap.Cancel):
+		m.namingPreset = false
+		return m.textInputs[m.activeInput].Focus()
+	case keymap.Matches(msg, m.keyMap.Confirm):
+		name := strings.TrimSpace(m.presetName.Value())
+		if name == "" {
+			return nil
+		}
+		m.namingPreset = false
+		return tea.Batch(m.textInputs[m.activeInput].Focus(), m.savePresetCmd(name))
+	}
+
+	var cmd tea.Cmd
+	m.presetName, cmd = m.presetName.Update(msg)
+	return cmd
+}
+
+// savePresetCmd stores the values currently in the variable form as a preset
+// of the selected prompt. Sensitive variables are left out since presets are
+// written to the prompts file.
+func (m *Model) savePresetCmd(name string) tea.Cmd {
+	preset := prompt.Preset{Name: name, Values: make(map[string]string)}
+	for i, label := range m.inputLabels {
+		if v, ok := m.selectedPrompt.Variable(label); ok && v.Sensitive {
+			continue
+		}
+		if value := m.inputValue(i); value != "" {
+			preset.Values[label] = value
+		}
+	}
+	key := m.selectedPrompt.Key()
+
+	return func() tea.Msg {
+		err := m.yamlRepo.UpdatePrompt(key, func(p *prompt.Prompt) {
+			p.SetPreset(preset)
+		})
+		if err != nil {
+			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("Error saving preset: %v", err))}
+		}
+		return presetSavedMsg{preset: preset}
+	}
+}
+
+func (m *Model) openPresetPicker() {
+	m.state = config.StatePresetPicker
+	m.presetCursor = 0
+}
+
+func (m *Model) updatePresetPicker(msg tea.KeyMsg) tea.Cmd {
+	presets := m.selectedPrompt.Presets
+
+	switch {
+	case keymap.Matches(msg, m.keyMap.Up):
+		m.presetCursor = (m.presetCursor - 1 + len(presets)) % len(presets)
+	case keymap.Matches(msg, m.keyMap.Down):
+		m.presetCursor = (m.presetCursor + 1) % len(presets)
+	case keymap.Matches(msg, m.keyMap.Select):
+		preset := presets[m.presetCursor]
+		m.state = config.StatePromptView
+		vars := make(map[string]string, len(preset.Values))
+		for k, v := range preset.Values {
+			vars[k] = v
+		}
+		return m.requestCopy(m.selectedPrompt, vars)
+	case keymap.Matches(msg, m.keyMap.Back):
+		m.state = config.StatePromptView
+	}
+	return nil
+}
+
+func (m Model) renderPresetPicker() string {
+	var view strings.Builder
+	view.WriteString(m.styles.Title.Render(fmt.Sprintf("Presets for: %s", m.selectedPrompt.Title)) + "\n\n")
+
+	for i, preset := range m.selectedPrompt.Presets {
+		cursor := "  "
+		name := preset.Name
+		if i == m.presetCursor {
+			cursor = m.styles.InputLabel.Render("> ")
+			name = m.styles.InputLabel.Render(name)
+		}
+		view.WriteString(cursor + name + "\n")
+		view.WriteString("    " + lipgloss.NewStyle().Faint(true).Render(presetSummary(preset, m.width-8)) + "\n\n")
+	}
+
+	view.WriteString(lipgloss.NewStyle().Faint(true).Render("Enter to render and copy, Esc to go back."))
+	return m.styles.Doc.Render(view.String())
+}
+
+func presetSummary(preset prompt.Preset, width int) string {
+	names := make([]string, 0, len(preset.Values))
+	for name := range preset.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := strings.ReplaceAll(preset.Values[name], "\n", " ")
+		pairs[i] = name + "=" + value
+	}
+	summary := strings.Join(pairs, ", ")
+	if width > 1 {
+		summary = ansi.Truncate(summary, width, "…")
+	}
+	return summary
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/renatogalera/promptgen/internal/domain/tokens"
+)
+
+const (
+	previewMinSplitWidth = 100
+	previewMinHeight     = 5
+
+	// Private-use runes marking the value of the focused variable in the
+	// rendered preview; they are valid XML characters and never typed.
+	previewMarkStart = "\uE000"
+	previewMarkEnd   = "\uE001"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\{[^{}]+\}\}\}`)
+
+// previewSplit reports whether the preview sits beside the variable form,
+// and the width left for the form.
+func (m Model) previewSplit() (bool, int) {
+	if m.width < previewMinSplitWidth {
+		return false, m.width
+	}
+	return true, max(m.width*2/5, 40)
+}
+
+func (m *Model) resizeVariableForm() {
+	split, formWidth := m.previewSplit()
+
+	inputWidth := max(formWidth-m.styles.Doc.GetHorizontalFrameSize()-lipgloss.Width("┃ ")-2, 20)
+	for i := range m.textInputs {
+		m.textInputs[i].Width = inputWidth
+	}
+
+	frameW := m.styles.Viewport.GetHorizontalFrameSize()
+	frameH := m.styles.Viewport.GetVerticalFrameSize()
+	countHeight := 1
+	if split {
+		m.preview.Width = m.width - formWidth - frameW
+		m.preview.Height = max(m.availableHeight()-countHeight-frameH, previewMinHeight)
+		return
+	}
+	m.preview.Width = m.width - frameW
+	m.preview.Height = max(m.availableHeight()-lipgloss.Height(m.renderInputForm())-countHeight-frameH, previewMinHeight)
+}
+
+// refreshPreview re-renders the prompt with the values typed so far and
+// scrolls to the variable being edited.
+func (m *Model) refreshPreview(scroll bool) {
+	active := ""
+	if m.activeInput < len(m.inputLabels) {
+		active = m.inputLabels[m.activeInput]
+	}
+
+	vars := make(map[string]string, len(m.builtinValues)+len(m.inputLabels))
+	for k, v := range m.builtinValues {
+		vars[k] = v
+	}
+	for i, label := range m.inputLabels {
+		value := m.inputValue(i)
+		if value == "" {
+			continue
+		}
+		if label == active {
+			value = previewMarkStart + value + previewMarkEnd
+		}
+		vars[label] = value
+	}
+
+	output, err := m.renderer.Preview(m.previewPrompt(), vars, m.format)
+	if err != nil {
+		m.preview.SetContent(m.styles.Error.Render(err.Error()))
+		return
+	}
+	m.previewCount = tokens.Estimate(stripPreviewMarks(output), m.family)
+
+	activePlaceholder := "{{{" + active + "}}}"
+	wrapped := ansi.Wrap(output, max(m.preview.Width, 10), "")
+
+	target := -1
+	for i, line := range strings.Split(wrapped, "\n") {
+		if strings.Contains(line, previewMarkStart) || (active != "" && strings.Contains(line, activePlaceholder)) {
+			target = i
+			break
+		}
+	}
+
+	m.preview.SetContent(m.highlightPreview(wrapped, activePlaceholder))
+	if scroll && target >= 0 {
+		m.preview.SetYOffset(max(target-m.preview.Height/3, 0))
+	}
+}
+
+// highlightPreview styles the focused value and every unfilled placeholder.
+func (m Model) highlightPreview(text, activePlaceholder string) string {
+	var out strings.Builder
+	inActive := false
+
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			out.WriteByte('\n')
+		}
+		for line != "" {
+			if inActive {
+				end := strings.Index(line, previewMarkEnd)
+				if end < 0 {
+					out.WriteString(m.styles.ActiveValue.Render(line))
+					break
+				}
+				out.WriteString(m.styles.ActiveValue.Render(line[:end]))
+				line = line[end+len(previewMarkEnd):]
+				inActive = false
+				continue
+			}
+
+			segment := line
+			start := strings.Index(line, previewMarkStart)
+			if start >= 0 {
+				segment = line[:start]
+			}
+			out.WriteString(placeholderPattern.ReplaceAllStringFunc(segment, func(p string) string {
+				if p == activePlaceholder {
+					return m.styles.ActiveValue.Render(p)
+				}
+				return m.styles.Placeholder.Render(p)
+			}))
+			if start < 0 {
+				break
+			}
+			line = line[start+len(previewMarkStart):]
+			inActive = true
+		}
+	}
+
+	return out.String()
+}
+
+func (m Model) renderVariableInput() string {
+	form := m.renderInputForm()
+	preview := " " + m.renderTokenCount(m.previewCount) + "\n" + m.styles.Viewport.Render(m.preview.View())
+
+	if split, formWidth := m.previewSplit(); split {
+		return lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(formWidth).Render(form), preview)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, form, preview)
+}
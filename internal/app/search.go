@@ -0,0 +1,221 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/query"
+	"github.com/renatogalera/promptgen/internal/domain/search"
+	"github.com/renatogalera/promptgen/pkg/filter"
+)
+
+// searchFilter is the list's filter function. Plain words are ranked by the
+// text filter over title, tags and description, or looked up in the full-text
+// index when that mode is on; queries using fields, negation, OR groups or
+// phrases are evaluated against the prompts behind the list items. The list
+// runs the filter in a command, so the state is shared through a pointer.
+type searchFilter struct {
+	mu        sync.RWMutex
+	library   []prompt.Prompt
+	index     *search.Index
+	prompts   []prompt.Prompt
+	fields    []string
+	positions map[int]int
+	fullText  bool
+	text      list.FilterFunc
+}
+
+func newSearchFilter(text list.FilterFunc) *searchFilter {
+	return &searchFilter{text: text}
+}
+
+// setLibrary records every prompt of the library and their full-text index.
+func (f *searchFilter) setLibrary(prompts []prompt.Prompt, index *search.Index) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.library = prompts
+	f.index = index
+}
+
+// setVisible records the items shown in the list, in order, and where each
+// sits in the library, so prompts sharing a title are kept apart.
+func (f *searchFilter) setVisible(items []prompt.Item) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prompts = make([]prompt.Prompt, len(items))
+	f.fields = make([]string, len(items))
+	f.positions = make(map[int]int, len(items))
+	for i, item := range items {
+		f.prompts[i] = item.Prompt
+		f.fields[i] = strings.Join(item.SearchFields(), filter.FieldSeparator)
+		f.positions[item.Index] = i
+	}
+}
+
+func (f *searchFilter) FullText() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.fullText
+}
+
+func (f *searchFilter) toggleFullText() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fullText = !f.fullText
+}
+
+func (f *searchFilter) Filter(term string, targets []string) []list.Rank {
+	q, err := query.Parse(term)
+	if err != nil {
+		return nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	var ranks []list.Rank
+	switch {
+	case q.Structured():
+		for i := range targets {
+			if i < len(f.prompts) && q.Match(f.prompts[i]) {
+				ranks = append(ranks, list.Rank{Index: i})
+			}
+		}
+	case f.fullText && f.index != nil:
+		words := search.Tokenize(term)
+		for _, hit := range f.index.Search(term) {
+			if i, ok := f.positions[hit.Doc]; ok && i < len(targets) {
+				ranks = append(ranks, list.Rank{Index: i, MatchedIndexes: titleMatches(f.library[hit.Doc].Title, words)})
+			}
+		}
+	default:
+		if len(f.fields) == len(targets) {
+			targets = f.fields
+		}
+		ranks = f.text(term, targets)
+	}
+	return ranks
+}
+
+// snippet returns the line of the item's full text matching the filter.
+func (f *searchFilter) snippet(item prompt.Item, term string) (search.Snippet, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.index == nil {
+		return search.Snippet{}, false
+	}
+	return f.index.Snippet(item.Index, term)
+}
+
+// titleMatches returns the rune positions of the words within the title.
+func titleMatches(title string, words []string) []int {
+	lower := []rune(strings.ToLower(title))
+	var matched []int
+	for _, word := range words {
+		w := []rune(word)
+		for i := 0; i+len(w) <= len(lower); i++ {
+			if string(lower[i:i+len(w)]) == word {
+				for j := range w {
+					matched = append(matched, i+j)
+				}
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// searchDelegate renders list items as the default delegate does and, in
+// full-text mode, adds the line that matched the filter under each item.
+type searchDelegate struct {
+	list.DefaultDelegate
+	search *searchFilter
+}
+
+func newSearchDelegate(s *searchFilter) searchDelegate {
+	return searchDelegate{DefaultDelegate: list.NewDefaultDelegate(), search: s}
+}
+
+func (d searchDelegate) Height() int {
+	if d.search.FullText() {
+		return d.DefaultDelegate.Height() + 1
+	}
+	return d.DefaultDelegate.Height()
+}
+
+func (d searchDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	d.DefaultDelegate.Render(w, m, index, item)
+	if !d.search.FullText() {
+		return
+	}
+
+	text := ""
+	if i, ok := item.(prompt.Item); ok && m.FilterState() != list.Unfiltered {
+		if snippet, ok := d.search.snippet(i, m.FilterValue()); ok {
+			text = fmt.Sprintf("%s:%d: %s", snippet.Field, snippet.Line, snippet.Text)
+		}
+	}
+
+	var lineStyle lipgloss.Style
+	switch {
+	case m.FilterState() == list.Filtering && m.FilterValue() == "":
+		lineStyle = d.Styles.DimmedDesc
+	case index == m.Index() && m.FilterState() != list.Filtering:
+		lineStyle = d.Styles.SelectedDesc
+	default:
+		lineStyle = d.Styles.NormalDesc
+	}
+	width := m.Width() - lineStyle.GetHorizontalFrameSize()
+	fmt.Fprintf(w, "\n%s", lineStyle.Render(ansi.Truncate(text, max(width, 0), "…")))
+}
+
+// checkQuery reports a malformed search query in the status line and clears
+// the report once the query is fixed.
+func (m *Model) checkQuery() {
+	_, err := query.Parse(m.list.FilterValue())
+	switch {
+	case err != nil:
+		m.statusMessage = m.styles.Error.Render(err.Error())
+		m.queryError = true
+	case m.queryError:
+		m.statusMessage = ""
+		m.queryError = false
+	}
+}
+
+// toggleFullText switches plain-word search between title/tags and the full
+// text, re-running an active filter in the new mode.
+func (m *Model) toggleFullText() tea.Cmd {
+	m.search.toggleFullText()
+	m.list.Title = m.listTitle()
+	m.list.SetDelegate(newSearchDelegate(m.search))
+	return m.list.SetItems(m.list.Items())
+}
+
+func (m Model) listTitle() string {
+	title := "AI Prompts"
+	if crumbs := m.breadcrumbs(); crumbs != "" {
+		title += " › " + crumbs
+	}
+	if tags := m.selectedTags(); len(tags) > 0 {
+		separator := " | "
+		if m.tags.matchAll {
+			separator = " + "
+		}
+		title += " · " + strings.Join(tags, separator)
+	}
+	if m.search.FullText() {
+		title += " · full text"
+	}
+	if m.sortMode != prompt.SortTitle {
+		title += " · " + m.sortMode.Label()
+	}
+	return title
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/search"
+	"github.com/renatogalera/promptgen/pkg/filter"
+)
+
+func TestSearchFilterKeepsDuplicateTitlesApart(t *testing.T) {
+	library := []prompt.Prompt{
+		{Title: "Same", Content: "alpha"},
+		{Title: "Same", Content: "beta"},
+		{Title: "Other", Content: "beta"},
+	}
+	f := newSearchFilter(filter.FuzzyFilter)
+	f.setLibrary(library, search.Build(library, nil))
+	f.toggleFullText()
+	// The list shows the last two prompts, in reverse.
+	f.setVisible([]prompt.Item{{Prompt: library[2], Index: 2}, {Prompt: library[1], Index: 1}})
+	targets := []string{"Other", "Same"}
+
+	var got []int
+	for _, rank := range f.Filter("beta", targets) {
+		got = append(got, rank.Index)
+	}
+	if want := []int{1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter(beta) = %v, want %v", got, want)
+	}
+	if ranks := f.Filter("alpha", targets); len(ranks) != 0 {
+		t.Errorf("Filter(alpha) = %v, want nothing: the prompt is not in the list", ranks)
+	}
+
+	snippet, ok := f.snippet(prompt.Item{Prompt: library[1], Index: 1}, "beta")
+	if !ok || snippet.Text != "beta" {
+		t.Errorf("snippet() = %+v, %v, want the line of the second prompt", snippet, ok)
+	}
+	if _, ok := f.snippet(prompt.Item{Prompt: library[0], Index: 0}, "beta"); ok {
+		t.Errorf("snippet() found a line of another prompt with the same title")
+	}
+}
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/secrets"
+	"github.com/renatogalera/promptgen/internal/ui/keymap"
+)
+
+// secretsFoundMsg holds back a delivery whose output contains secrets until
+// the user decides what to do with them.
+type secretsFoundMsg struct {
+	delivery delivery
+	matches  []secrets.Match
+}
+
+func newSecretScanner(settings config.SecretSettings) (*secrets.Scanner, error) {
+	if settings.Disabled {
+		return nil, nil
+	}
+	patterns := make([]secrets.Pattern, len(settings.Patterns))
+	for i, p := range settings.Patterns {
+		patterns[i] = secrets.Pattern{Name: p.Name, Regex: p.Regex}
+	}
+	scanner, err := secrets.NewScanner(patterns, !settings.DisableEntropy)
+	if err != nil {
+		scanner, _ = secrets.NewScanner(nil, !settings.DisableEntropy)
+	}
+	return scanner, err
+}
+
+func (m *Model) showSecrets(msg secretsFoundMsg) {
+	m.pendingSecrets = &msg
+	m.state = config.StateSecretConfirm
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+
+	output := msg.delivery.result.Output
+	m.viewport.SetContent(m.highlightSecrets(output, msg.matches))
+	m.viewport.SetYOffset(strings.Count(output[:msg.matches[0].Start], "\n") - 2)
+}
+
+func (m *Model) updateSecretConfirm(msg tea.KeyMsg) tea.Cmd {
+	pending := m.pendingSecrets
+	switch {
+	case keymap.Matches(msg, m.keyMap.Redact):
+		d := pending.delivery
+		d.vars = m.redactVariables(d.vars, d.result.Output, pending.matches)
+		d.result.Output = secrets.Redact(d.result.Output, pending.matches)
+		m.closeSecretConfirm()
+		return m.deliverCmd(d)
+	case keymap.Matches(msg, m.keyMap.Accept):
+		d := pending.delivery
+		d.record = false
+		m.closeSecretConfirm()
+		return m.deliverCmd(d)
+	case keymap.Matches(msg, m.keyMap.Reject), keymap.Matches(msg, m.keyMap.Cancel):
+		m.closeSecretConfirm()
+		m.statusMessage = m.styles.Error.Render("Copy cancelled: the output contains secrets")
+		m.statusCmd = m.clearStatusCmd()
+		return m.statusCmd
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return cmd
+}
+
+// redactVariables returns a copy of vars without the secrets found in the
+// output or in the values themselves, so the history entry of a redacted
+// copy holds none either.
+func (m *Model) redactVariables(vars map[string]string, output string, matches []secrets.Match) map[string]string {
+	redacted := make(map[string]string, len(vars))
+	for name, value := range vars {
+		value = secrets.Redact(value, m.secrets.Scan(value))
+		for _, match := range matches {
+			value = strings.ReplaceAll(value, output[match.Start:match.End], "[REDACTED: "+match.Rule+"]")
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+func (m *Model) closeSecretConfirm() {
+	m.pendingSecrets = nil
+	m.state = m.promptState()
+	m.viewport.SetContent(m.selectedPrompt.Content)
+	m.viewport.GotoTop()
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+}
+
+// highlightSecrets styles every match, line by line so a multi-line match
+// such as a private key keeps its layout.
+func (m Model) highlightSecrets(text string, matches []secrets.Match) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		b.WriteString(text[last:match.Start])
+		lines := strings.Split(text[match.Start:match.End], "\n")
+		for i, line := range lines {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			if line != "" {
+				b.WriteString(m.styles.Secret.Render(line))
+			}
+		}
+		last = match.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func (m Model) renderSecretHeader() string {
+	var header strings.Builder
+	header.WriteString(m.styles.Title.Render("Secrets found") + "\n")
+	if m.pendingSecrets == nil {
+		return header.String()
+	}
+
+	var rules []string
+	counts := make(map[string]int)
+	for _, match := range m.pendingSecrets.matches {
+		if counts[match.Rule] == 0 {
+			rules = append(rules, match.Rule)
+		}
+		counts[match.Rule]++
+	}
+	for i, rule := range rules {
+		if counts[rule] > 1 {
+			rules[i] = fmt.Sprintf("%s ×%d", rule, counts[rule])
+		}
+	}
+
+	header.WriteString(fmt.Sprintf("  The output of %s looks like it contains %s.\n",
+		m.styles.InputLabel.Render(m.pendingSecrets.delivery.prompt.Title), m.styles.Error.Render(strings.Join(rules, ", "))))
+	header.WriteString("  " + lipgloss.NewStyle().Faint(true).Render("Press r to redact them, y to use the output as it is, n or Esc to cancel.") + "\n")
+	return header.String()
+}
+
+func (m Model) renderSecretConfirm() string {
+	return m.renderSecretHeader() + m.styles.Viewport.Render(m.viewport.View())
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/render"
+)
+
+// sink is where the copy action delivers the rendered prompt.
+type sink int
+
+const (
+	sinkClipboard sink = iota
+	sinkFile
+	sinkStdout
+)
+
+// Options change how the application delivers rendered prompts.
+type Options struct {
+	// Print makes the copy action end the program and hand the output to
+	// the caller, which prints it once the TUI is gone.
+	Print bool
+	// Pick strips the interface down to the list and the variable form:
+	// selecting a prompt renders it to stdout and ends the program.
+	Pick bool
+	// Format is the initial output format; empty means XML.
+	Format render.Format
+}
+
+// copySink is the sink of the copy key: the clipboard, or stdout in print
+// mode.
+func (m Model) copySink() sink {
+	if m.options.Print || m.options.Pick {
+		return sinkStdout
+	}
+	return sinkClipboard
+}
+
+// writeOutputFile saves the output in dir as <prompt>-<timestamp>.<ext> and
+// returns its path. A save in the same second gets a -2, -3, ... suffix
+// instead of overwriting the earlier file.
+func writeOutputFile(dir string, p prompt.Prompt, format render.Format, output string, at time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("could not create output directory '%s': %w", dir, err)
+	}
+	base := fmt.Sprintf("%s-%s", fileSlug(p.Key()), at.Format("20060102-150405"))
+	for n := 1; ; n++ {
+		name := base
+		if n > 1 {
+			name += fmt.Sprintf("-%d", n)
+		}
+		path := filepath.Join(dir, name+"."+format.Extension())
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("could not write '%s': %w", path, err)
+		}
+		_, err = f.WriteString(output)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("could not write '%s': %w", path, err)
+		}
+		return path, nil
+	}
+}
+
+// fileSlug turns a prompt key into a lowercase file name of letters, digits
+// and dashes.
+func fileSlug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "prompt"
+	}
+	return slug
+}
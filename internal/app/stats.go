@@ -0,0 +1,53 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/renatogalera/promptgen/internal/config"
+	"github.com/renatogalera/promptgen/internal/domain/stats"
+	"github.com/renatogalera/promptgen/internal/ui/keymap"
+)
+
+type statsLoadedMsg struct{ report stats.Report }
+
+func (m *Model) loadStatsCmd() tea.Cmd {
+	prompts := m.prompts.Prompts
+	return func() tea.Msg {
+		events, err := m.events.Load()
+		if err != nil {
+			return errMsg{err: err}
+		}
+		return statsLoadedMsg{report: stats.Compute(events, prompts, time.Now(), stats.DefaultOptions)}
+	}
+}
+
+func (m *Model) showStats(report stats.Report) {
+	var content strings.Builder
+	if !m.settings.Analytics.Enabled {
+		content.WriteString("Usage analytics are off. Set analytics.enabled: true in the config file to record uses.\n\n")
+	}
+	report.WriteText(&content)
+
+	m.state = config.StateStats
+	m.viewport.SetContent(content.String())
+	m.viewport.GotoTop()
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+}
+
+func (m *Model) updateStats(msg tea.KeyMsg) tea.Cmd {
+	if keymap.Matches(msg, m.keyMap.Back) {
+		m.state = config.StatePromptList
+		m.viewport.SetContent("")
+		return nil
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return cmd
+}
+
+func (m Model) renderStats() string {
+	return m.styles.Title.Render("Usage statistics") + "\n" + m.styles.Viewport.Render(m.viewport.View())
+}
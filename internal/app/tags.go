@@ -0,0 +1,234 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/ui/keymap"
+)
+
+// sidebarWidth is the width of the tag and folder panes beside the list.
+const sidebarWidth = 30
+
+// tagPane is the sidebar of the prompt list listing every tag with its count.
+// Selected tags narrow the list to prompts carrying all of them, or any of
+// them when matchAll is off.
+type tagPane struct {
+	visible  bool
+	focused  bool
+	cursor   int
+	selected map[string]bool
+	matchAll bool
+	renaming bool
+	input    textinput.Model
+}
+
+type tagRenamedMsg struct {
+	from, to string
+	count    int
+	merged   bool
+}
+
+func (m *Model) toggleTagPane() {
+	m.tags.visible = !m.tags.visible
+	m.tags.focused = m.tags.visible
+	m.folders.visible, m.folders.focused = false, false
+	m.cancelMove()
+	*m = m.updateWindowSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+}
+
+func (m *Model) updateTagPane(msg tea.KeyMsg) tea.Cmd {
+	if m.tags.renaming {
+		return m.updateTagRename(msg)
+	}
+
+	counts := m.prompts.TagCounts()
+	switch {
+	case keymap.Matches(msg, m.keyMap.Tags):
+		m.toggleTagPane()
+	case keymap.Matches(msg, m.keyMap.Tab), keymap.Matches(msg, m.keyMap.Back):
+		m.tags.focused = false
+	case len(counts) == 0:
+	case keymap.Matches(msg, m.keyMap.Up):
+		m.tags.cursor = max(m.tags.cursor-1, 0)
+	case keymap.Matches(msg, m.keyMap.Down):
+		m.tags.cursor = min(m.tags.cursor+1, len(counts)-1)
+	case keymap.Matches(msg, m.keyMap.ToggleTag), keymap.Matches(msg, m.keyMap.Select):
+		tag := counts[m.tags.cursor].Tag
+		if m.tags.selected[tag] {
+			delete(m.tags.selected, tag)
+		} else {
+			m.tags.selected[tag] = true
+		}
+		return m.refreshListItems()
+	case keymap.Matches(msg, m.keyMap.TagMode):
+		m.tags.matchAll = !m.tags.matchAll
+		return m.refreshListItems()
+	case keymap.Matches(msg, m.keyMap.ClearTags):
+		m.tags.selected = make(map[string]bool)
+		return m.refreshListItems()
+	case keymap.Matches(msg, m.keyMap.RenameTag):
+		m.tags.renaming = true
+		m.tags.input = textinput.New()
+		m.tags.input.Prompt = "┃ "
+		m.tags.input.PromptStyle = m.styles.InputLabel
+		m.tags.input.Width = sidebarWidth - 6
+		m.tags.input.SetValue(counts[m.tags.cursor].Tag)
+		return m.tags.input.Focus()
+	}
+	return nil
+}
+
+func (m *Model) updateTagRename(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case keymap.Matches(msg, m.keyMap.Cancel):
+		m.tags.renaming = false
+		return nil
+	case keymap.Matches(msg, m.keyMap.Confirm):
+		m.tags.renaming = false
+		from := m.prompts.TagCounts()[m.tags.cursor].Tag
+		to := strings.TrimSpace(m.tags.input.Value())
+		if to == "" || to == from || strings.Contains(to, ",") {
+			return nil
+		}
+		return m.renameTagCmd(from, to)
+	}
+
+	var cmd tea.Cmd
+	m.tags.input, cmd = m.tags.input.Update(msg)
+	return cmd
+}
+
+// renameTagCmd rewrites the tag in every prompt of the library in one save.
+func (m *Model) renameTagCmd(from, to string) tea.Cmd {
+	merged := false
+	for _, tc := range m.prompts.TagCounts() {
+		merged = merged || tc.Tag == to
+	}
+
+	return func() tea.Msg {
+		count, err := m.yamlRepo.RenameTag(from, to)
+		if err != nil {
+			return statusMsg{message: m.styles.Error.Render(fmt.Sprintf("Error renaming tag: %v", err))}
+		}
+		return tagRenamedMsg{from: from, to: to, count: count, merged: merged}
+	}
+}
+
+func (m *Model) handleTagRenamed(msg tagRenamedMsg) tea.Cmd {
+	if m.tags.selected[msg.from] {
+		delete(m.tags.selected, msg.from)
+		m.tags.selected[msg.to] = true
+	}
+
+	verb := fmt.Sprintf("Renamed tag '%s' to '%s'", msg.from, msg.to)
+	if msg.merged {
+		verb = fmt.Sprintf("Merged tag '%s' into '%s'", msg.from, msg.to)
+	}
+	m.statusMessage = m.styles.Success.Render(fmt.Sprintf("%s in %d prompt(s)", verb, msg.count))
+	m.statusCmd = m.clearStatusCmd()
+	return tea.Batch(m.statusCmd, m.loadPromptsCmd())
+}
+
+// visibleItems returns the prompts of the open folder matching the selected
+// tags, as list items indexed by their position in the library.
+func (m Model) visibleItems() []prompt.Item {
+	var visible []prompt.Item
+	for i, p := range m.prompts.Prompts {
+		if !p.InCategory(m.folders.current) {
+			continue
+		}
+		matched := 0
+		for tag := range m.tags.selected {
+			if p.HasTag(tag) {
+				matched++
+			}
+		}
+		if len(m.tags.selected) == 0 || matched == len(m.tags.selected) || (!m.tags.matchAll && matched > 0) {
+			visible = append(visible, prompt.Item{Prompt: p, Index: i})
+		}
+	}
+	return visible
+}
+
+// refreshListItems fills the list with the prompts of the open folder
+// matching the selected tags, re-running any active filter.
+func (m *Model) refreshListItems() tea.Cmd {
+	if m.tags.cursor >= len(m.prompts.TagCounts()) {
+		m.tags.cursor = max(len(m.prompts.TagCounts())-1, 0)
+	}
+	if !m.folderExists(m.folders.current) {
+		m.folders.current = ""
+	}
+
+	visible := m.sortItems(m.visibleItems())
+	m.search.setVisible(visible)
+	m.list.Title = m.listTitle()
+	items := make([]list.Item, len(visible))
+	for i, item := range visible {
+		items[i] = item
+	}
+	return m.list.SetItems(items)
+}
+
+func (m Model) selectedTags() []string {
+	var tags []string
+	for _, tc := range m.prompts.TagCounts() {
+		if m.tags.selected[tc.Tag] {
+			tags = append(tags, tc.Tag)
+		}
+	}
+	return tags
+}
+
+func (m Model) renderTagPane(height int) string {
+	paneStyle := m.styles.PromptList.Width(sidebarWidth - 2).Height(height - 2)
+	if m.tags.focused {
+		paneStyle = paneStyle.BorderForeground(lipgloss.Color("#7D56F4"))
+	}
+
+	mode := "any"
+	if m.tags.matchAll {
+		mode = "all"
+	}
+	var view strings.Builder
+	view.WriteString(" " + m.styles.InputLabel.Render("Tags") + lipgloss.NewStyle().Faint(true).Render(" · match "+mode) + "\n\n")
+
+	counts := m.prompts.TagCounts()
+	rows := max(height-paneStyle.GetVerticalFrameSize()-4, 1)
+	offset := max(m.tags.cursor-rows+1, 0)
+
+	for i := offset; i < len(counts) && i < offset+rows; i++ {
+		tc := counts[i]
+		check := "[ ]"
+		if m.tags.selected[tc.Tag] {
+			check = "[x]"
+		}
+		count := fmt.Sprintf("%d", tc.Count)
+		name := ansi.Truncate(tc.Tag, max(sidebarWidth-10-len(count), 1), "…")
+		row := fmt.Sprintf("%s %s", check, name)
+		row += strings.Repeat(" ", max(sidebarWidth-5-lipgloss.Width(row)-len(count), 1)) + count
+
+		if i == m.tags.cursor && m.tags.focused {
+			row = m.styles.InputLabel.Render("> " + row)
+		} else {
+			row = "  " + row
+		}
+		view.WriteString(row + "\n")
+	}
+	if len(counts) == 0 {
+		view.WriteString(lipgloss.NewStyle().Faint(true).Render("  No tags yet") + "\n")
+	}
+
+	if m.tags.renaming {
+		view.WriteString("\n " + m.tags.input.View())
+	}
+	return paneStyle.Render(view.String())
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/renatogalera/promptgen/internal/domain/tokens"
+)
+
+// tokenLimitMsg holds back a delivery whose output is over the token limit.
+type tokenLimitMsg struct {
+	delivery delivery
+	count    tokens.Count
+}
+
+// updateEstimate counts the selected prompt as the prompt view shows it,
+// with built-ins resolved and the docs attached once they are loaded.
+func (m *Model) updateEstimate() {
+	output, err := m.renderer.Preview(m.previewPrompt(), m.builtinValues, m.format)
+	if err != nil {
+		m.estimate = tokens.Count{}
+		return
+	}
+	m.estimate = tokens.Estimate(output, m.family)
+}
+
+func (m *Model) cycleTokenizer() {
+	m.family = m.family.Next()
+	m.updateEstimate()
+}
+
+func (m Model) overLimit(count tokens.Count) bool {
+	return m.settings.Tokens.Limit > 0 && count.Tokens > m.settings.Tokens.Limit
+}
+
+// renderTokenCount shows a count against the budget and limit: green within
+// the budget, red past it.
+func (m Model) renderTokenCount(count tokens.Count) string {
+	text := fmt.Sprintf("~%d tokens (%s) · %d chars", count.Tokens, m.family.Label, count.Chars)
+	budget := m.settings.Tokens.Budget
+	if budget <= 0 {
+		return lipgloss.NewStyle().Faint(true).Render(text)
+	}
+
+	text += fmt.Sprintf(" · budget %d", budget)
+	if m.overLimit(count) {
+		text += fmt.Sprintf(", over the %d limit", m.settings.Tokens.Limit)
+	}
+	if count.Tokens > budget {
+		return m.styles.Error.Render(text)
+	}
+	return m.styles.Success.Render(text)
+}
+
+func (m *Model) handleTokenLimit(msg tokenLimitMsg) tea.Cmd {
+	m.blockedCopy = &msg.delivery
+	m.statusMessage = m.styles.Error.Render(fmt.Sprintf(
+		"Not copied: ~%d tokens is over the limit of %d. Press ! to copy anyway.", msg.count.Tokens, m.settings.Tokens.Limit))
+	m.statusCmd = m.clearStatusCmd()
+	return m.statusCmd
+}
+
+// overrideLimitCmd delivers the copy last blocked by the token limit.
+func (m *Model) overrideLimitCmd() tea.Cmd {
+	if m.blockedCopy == nil {
+		return nil
+	}
+	d := *m.blockedCopy
+	m.blockedCopy = nil
+	d.overLimit = true
+	return func() tea.Msg {
+		return m.screenDelivery(d)
+	}
+}
+
+func stripPreviewMarks(s string) string {
+	return strings.NewReplacer(previewMarkStart, "", previewMarkEnd, "").Replace(s)
+}
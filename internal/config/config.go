@@ -18,4 +18,11 @@ const (
 	StatePromptView
 	StatePromptCreation
 	StateVariableInput
+	StateFilePicker
+	StateCommandConfirm
+	StatePresetPicker
+	StateStats
+	StateHistory
+	StateSecretConfirm
+	StateDocView
 )
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SettingsFilename          = "config.yaml"
+	DefaultHistoryLimit       = 10
+	DefaultOutputHistoryLimit = 50
+
+	SearchFuzzy     = "fuzzy"
+	SearchSubstring = "substring"
+)
+
+// Settings are the user preferences read from the config file.
+type Settings struct {
+	TrustedSources  []string              `yaml:"trusted_sources,omitempty"`
+	VariableHistory HistorySettings       `yaml:"variable_history,omitempty"`
+	Search          SearchSettings        `yaml:"search,omitempty"`
+	Analytics       AnalyticsSettings     `yaml:"analytics,omitempty"`
+	Clipboard       ClipboardSettings     `yaml:"clipboard,omitempty"`
+	Output          OutputSettings        `yaml:"output,omitempty"`
+	OutputHistory   OutputHistorySettings `yaml:"output_history,omitempty"`
+	Secrets         SecretSettings        `yaml:"secrets,omitempty"`
+	Tokens          TokenSettings         `yaml:"tokens,omitempty"`
+	Docs            DocSettings           `yaml:"docs,omitempty"`
+}
+
+// DocSettings control how the docs attached to prompts are read. Missing is
+// what happens when a doc cannot be read or a glob matches nothing: "error"
+// (the default) fails the render, "warn" leaves the doc out with a warning
+// and "skip" leaves it out silently. Limit caps the size of the docs of
+// prompts that set no doc_limit of their own.
+type DocSettings struct {
+	Missing string           `yaml:"missing,omitempty"`
+	Limit   DocLimitSettings `yaml:"limit,omitempty"`
+}
+
+// DocLimitSettings cap the docs of a prompt at Max bytes or tokens (Unit),
+// keeping what Strategy chooses: head, tail, head_tail, or the Sections
+// under the given headings.
+type DocLimitSettings struct {
+	Max      int      `yaml:"max,omitempty"`
+	Unit     string   `yaml:"unit,omitempty"`
+	Strategy string   `yaml:"strategy,omitempty"`
+	Sections []string `yaml:"sections,omitempty"`
+}
+
+// TokenSettings configure the token estimate of rendered prompts. Model is
+// the model family to estimate for; past Budget the count turns red, and
+// past Limit copying is refused unless overridden. Zero means no budget or
+// limit.
+type TokenSettings struct {
+	Model  string `yaml:"model,omitempty"`
+	Budget int    `yaml:"budget,omitempty"`
+	Limit  int    `yaml:"limit,omitempty"`
+}
+
+// SecretSettings control the scan for credentials in a rendered prompt
+// before it is copied. Patterns add named regular expressions to the
+// built-in rules; DisableEntropy turns off the check for random-looking
+// strings.
+type SecretSettings struct {
+	Disabled       bool            `yaml:"disabled,omitempty"`
+	DisableEntropy bool            `yaml:"disable_entropy,omitempty"`
+	Patterns       []SecretPattern `yaml:"patterns,omitempty"`
+}
+
+type SecretPattern struct {
+	Name  string `yaml:"name"`
+	Regex string `yaml:"regex"`
+}
+
+// OutputHistorySettings control the history of rendered prompts kept for the
+// history screen. Limit caps the number of entries.
+type OutputHistorySettings struct {
+	Disabled bool `yaml:"disabled,omitempty"`
+	Limit    int  `yaml:"limit,omitempty"`
+}
+
+func (h OutputHistorySettings) MaxEntries() int {
+	if h.Limit <= 0 {
+		return DefaultOutputHistoryLimit
+	}
+	return h.Limit
+}
+
+// OutputSettings choose where prompts saved with the save-to-file key go. Dir
+// defaults to the output folder of the configuration directory.
+type OutputSettings struct {
+	Dir string `yaml:"dir,omitempty"`
+}
+
+// Directory returns the output directory with ~ expanded.
+func (o OutputSettings) Directory() string {
+	if o.Dir != "" {
+		return ExpandHome(o.Dir)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "."
+	}
+	return filepath.Join(dir, "output")
+}
+
+// ClipboardSettings choose how copied prompts reach the clipboard. Backend is
+// "auto" (the default: Command when set, system clipboard, then OSC 52, then
+// File), "native", "osc52", "file" or "command". Command is a shell command
+// the text is piped into, such as wl-copy or pbcopy.
+type ClipboardSettings struct {
+	Backend string `yaml:"backend,omitempty"`
+	File    string `yaml:"file,omitempty"`
+	Command string `yaml:"command,omitempty"`
+}
+
+// AnalyticsSettings opt in to recording prompt usage events locally for
+// `promptgen stats`. Nothing is ever sent anywhere.
+type AnalyticsSettings struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+}
+
+// SearchSettings select how the prompt list filters. Mode is "fuzzy" (the
+// default) for ranked, typo-tolerant matching or "substring" to require every
+// word to appear verbatim.
+type SearchSettings struct {
+	Mode string `yaml:"mode,omitempty"`
+}
+
+// HistorySettings control which variable values are remembered between runs.
+// Exclude holds variable names or glob patterns such as "*token*".
+type HistorySettings struct {
+	Disabled bool     `yaml:"disabled,omitempty"`
+	Limit    int      `yaml:"limit,omitempty"`
+	Exclude  []string `yaml:"exclude,omitempty"`
+}
+
+func (h HistorySettings) MaxValues() int {
+	if h.Limit <= 0 {
+		return DefaultHistoryLimit
+	}
+	return h.Limit
+}
+
+// Remembers reports whether values of the named variable may be stored.
+func (h HistorySettings) Remembers(name string) bool {
+	return !h.Disabled && !h.Excludes(name)
+}
+
+// Excludes reports whether the named variable matches an Exclude pattern.
+func (h HistorySettings) Excludes(name string) bool {
+	lower := strings.ToLower(name)
+	for _, pattern := range h.Exclude {
+		if ok, _ := path.Match(strings.ToLower(pattern), lower); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustedSourcePaths returns the trusted sources with ~ expanded.
+func (s Settings) TrustedSourcePaths() []string {
+	paths := make([]string, len(s.TrustedSources))
+	for i, source := range s.TrustedSources {
+		paths[i] = ExpandHome(source)
+	}
+	return paths
+}
+
+// StatePath returns the location of the local state file.
+func StatePath() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "state.yaml")
+}
+
+// EventsPath returns the location of the local usage events file.
+func EventsPath() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "events.jsonl")
+}
+
+// HistoryPath returns the location of the rendered prompt history.
+func HistoryPath() string {
+	dir, err := Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "history.json")
+}
+
+// Dir returns the promptgen configuration directory.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", ConfigDirName), nil
+}
+
+// LoadSettings reads the config file from the configuration directory. A
+// missing file yields the default settings.
+func LoadSettings() (Settings, error) {
+	dir, err := Dir()
+	if err != nil {
+		return Settings{}, err
+	}
+	path := filepath.Join(dir, SettingsFilename)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Settings{}, nil
+		}
+		return Settings{}, fmt.Errorf("failed to read config file '%s': %w", path, err)
+	}
+
+	var settings Settings
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse config file '%s': %w", path, err)
+	}
+	switch settings.Search.Mode {
+	case "", SearchFuzzy, SearchSubstring:
+	default:
+		return Settings{}, fmt.Errorf("invalid search mode '%s' in '%s' (use %s or %s)", settings.Search.Mode, path, SearchFuzzy, SearchSubstring)
+	}
+	return settings, nil
+}
+
+// ExpandHome replaces a leading ~ with the user's home directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
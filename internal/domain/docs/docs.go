@@ -0,0 +1,134 @@
+// Package docs loads the documentation files attached to a prompt with doc:
+// and docs:, which may be paths or globs relative to the prompts file.
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/pkg/fileset"
+)
+
+// Policy decides what happens when a doc cannot be read or a glob matches
+// nothing.
+type Policy string
+
+const (
+	// PolicyError fails the render.
+	PolicyError Policy = "error"
+	// PolicyWarn leaves the doc out and reports it in the render notes.
+	PolicyWarn Policy = "warn"
+	// PolicySkip leaves the doc out silently.
+	PolicySkip Policy = "skip"
+)
+
+// ParsePolicy parses a policy name; the empty name is PolicyError.
+func ParsePolicy(s string) (Policy, error) {
+	switch Policy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", PolicyError:
+		return PolicyError, nil
+	case PolicyWarn:
+		return PolicyWarn, nil
+	case PolicySkip:
+		return PolicySkip, nil
+	}
+	return "", fmt.Errorf("unknown missing-doc policy '%s' (use error, warn or skip)", s)
+}
+
+// Patterns returns the prompt's doc patterns resolved for reading: ~ is
+// expanded and relative paths are joined to the directory of the prompts
+// file the prompt came from.
+func Patterns(p prompt.Prompt) []string {
+	declared := p.DocPatterns()
+	patterns := make([]string, len(declared))
+	for i, pattern := range declared {
+		patterns[i] = Resolve(pattern, p.Source)
+	}
+	return patterns
+}
+
+// Load reads the prompt's docs in declaration order, glob matches sorted by
+// path. Each doc is named by its path relative to the prompts file when it
+// lies below it.
+func Load(p prompt.Prompt, policy Policy) ([]prompt.File, []string, error) {
+	var files []prompt.File
+	var notes []string
+	seen := make(map[string]bool)
+
+	for i, pattern := range Patterns(p) {
+		paths, err := fileset.Expand(pattern)
+		if err == nil && len(paths) == 0 {
+			err = fmt.Errorf("no files match '%s'", p.DocPatterns()[i])
+		}
+		for _, path := range paths {
+			if err != nil {
+				break
+			}
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+
+			var data []byte
+			data, err = os.ReadFile(path)
+			if err != nil {
+				err = fmt.Errorf("failed to read '%s': %w", path, err)
+				break
+			}
+			if fileset.IsBinary(data) {
+				notes = append(notes, fmt.Sprintf("skipped doc %s: binary file", path))
+				continue
+			}
+			files = append(files, prompt.File{Path: displayPath(path, p.Source), Content: string(data)})
+		}
+
+		if err != nil {
+			switch policy {
+			case PolicySkip:
+			case PolicyWarn:
+				notes = append(notes, fmt.Sprintf("doc left out: %v", err))
+			default:
+				return nil, notes, fmt.Errorf("error loading doc: %w", err)
+			}
+		}
+	}
+	return files, notes, nil
+}
+
+// Text returns the docs joined together, leaving out any it cannot read.
+func Text(p prompt.Prompt) string {
+	files, _, _ := Load(p, PolicySkip)
+	texts := make([]string, len(files))
+	for i, f := range files {
+		texts[i] = f.Content
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Resolve resolves a path written in the prompts file at source: ~ is
+// expanded and a relative path is joined to the directory of the file.
+func Resolve(pattern, source string) string {
+	if pattern == "~" || strings.HasPrefix(pattern, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(pattern, "~"))
+		}
+	}
+	if filepath.IsAbs(pattern) || source == "" {
+		return pattern
+	}
+	return filepath.Join(filepath.Dir(source), pattern)
+}
+
+func displayPath(path, source string) string {
+	if source == "" {
+		return path
+	}
+	rel, err := filepath.Rel(filepath.Dir(source), path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,256 @@
+package docs
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/tokens"
+)
+
+// Unit is what a limit counts.
+type Unit string
+
+const (
+	UnitBytes  Unit = "bytes"
+	UnitTokens Unit = "tokens"
+)
+
+// Strategy decides which part of a doc is kept when it is cut.
+type Strategy string
+
+const (
+	// StrategyHead keeps the beginning.
+	StrategyHead Strategy = "head"
+	// StrategyTail keeps the end.
+	StrategyTail Strategy = "tail"
+	// StrategyHeadTail keeps both ends around an elision marker.
+	StrategyHeadTail Strategy = "head_tail"
+	// StrategySections keeps the sections under the given headings, then
+	// cuts the rest at the head if it is still over the limit.
+	StrategySections Strategy = "sections"
+)
+
+// Limit caps the size of the docs of a prompt. Max is shared by the docs in
+// order: each is cut to what the ones before it left, and those past the
+// limit are left out. Tokens are estimated for Family.
+type Limit struct {
+	Max      int
+	Unit     Unit
+	Strategy Strategy
+	Sections []string
+	Family   tokens.Family
+}
+
+// Cut records what a limit removed from a doc.
+type Cut struct {
+	Path     string
+	From, To int
+	Unit     Unit
+	Strategy Strategy
+	// Dropped is why the whole doc was left out, if it was.
+	Dropped string
+}
+
+func (c Cut) String() string {
+	if c.Dropped != "" {
+		return fmt.Sprintf("doc %s (%d %s) left out: %s", c.Path, c.From, c.Unit, c.Dropped)
+	}
+	return fmt.Sprintf("doc %s cut from %d to %d %s (%s)", c.Path, c.From, c.To, c.Unit, c.Strategy)
+}
+
+// With returns the limit with the fields set in spec replacing its own. A
+// max without a unit keeps the limit's unit, bytes if it has none.
+func (l Limit) With(spec prompt.DocLimit) (Limit, error) {
+	if spec.Max < 0 {
+		return Limit{}, fmt.Errorf("invalid doc limit %d: must not be negative", spec.Max)
+	}
+	if spec.Max > 0 {
+		l.Max = spec.Max
+	}
+	switch Unit(strings.ToLower(spec.Unit)) {
+	case "":
+	case UnitBytes:
+		l.Unit = UnitBytes
+	case UnitTokens:
+		l.Unit = UnitTokens
+	default:
+		return Limit{}, fmt.Errorf("unknown doc limit unit '%s' (use bytes or tokens)", spec.Unit)
+	}
+	switch s := Strategy(strings.ToLower(spec.Strategy)); s {
+	case "":
+	case StrategyHead, StrategyTail, StrategyHeadTail, StrategySections:
+		l.Strategy = s
+	default:
+		return Limit{}, fmt.Errorf("unknown doc limit strategy '%s' (use head, tail, head_tail or sections)", spec.Strategy)
+	}
+	if len(spec.Sections) > 0 {
+		l.Sections = spec.Sections
+	}
+	if l.Strategy == StrategySections && len(l.Sections) == 0 {
+		return Limit{}, fmt.Errorf("doc limit strategy 'sections' needs a list of sections")
+	}
+	if l.Unit == "" {
+		l.Unit = UnitBytes
+	}
+	if l.Strategy == "" {
+		l.Strategy = StrategyHead
+	}
+	return l, nil
+}
+
+// Apply cuts the docs to the limit, reporting every doc it changed.
+func (l Limit) Apply(files []prompt.File) ([]prompt.File, []Cut) {
+	if l.Max <= 0 && l.Strategy != StrategySections {
+		return files, nil
+	}
+
+	var kept []prompt.File
+	var cuts []Cut
+	remaining := l.Max
+	for _, f := range files {
+		content := f.Content
+		cut := Cut{Path: f.Path, From: l.size(content), Unit: l.Unit, Strategy: l.Strategy}
+		if l.Strategy == StrategySections {
+			if content = sections(content, l.Sections); content == "" {
+				cut.Dropped = "no section matches " + strings.Join(l.Sections, ", ")
+				cuts = append(cuts, cut)
+				continue
+			}
+		}
+		if l.Max > 0 {
+			if remaining <= 0 {
+				cut.Dropped = fmt.Sprintf("the %d %s doc limit is used up", l.Max, l.Unit)
+				cuts = append(cuts, cut)
+				continue
+			}
+			if l.size(content) > remaining {
+				content = l.cut(content, remaining)
+			}
+			remaining -= l.size(content)
+		}
+		if content != f.Content {
+			cut.To = l.size(content)
+			cuts = append(cuts, cut)
+		}
+		kept = append(kept, prompt.File{Path: f.Path, Content: content})
+	}
+	return kept, cuts
+}
+
+func (l Limit) size(s string) int {
+	if l.Unit == UnitTokens {
+		family := l.Family
+		if family.Name == "" {
+			family = tokens.Families[0]
+		}
+		return tokens.Estimate(s, family).Tokens
+	}
+	return len(s)
+}
+
+// cut shortens s to at most n units with the limit's strategy.
+func (l Limit) cut(s string, n int) string {
+	switch l.Strategy {
+	case StrategyTail:
+		return s[l.tailStart(s, n):]
+	case StrategyHeadTail:
+		marker := fmt.Sprintf("\n\n[… %%d %s omitted …]\n\n", l.Unit)
+		budget := n - l.size(fmt.Sprintf(marker, l.size(s)))
+		if budget <= 0 {
+			return s[:l.headEnd(s, n)]
+		}
+		head := s[:l.headEnd(s, budget-budget/2)]
+		tail := s[l.tailStart(s, budget/2):]
+		omitted := l.size(s) - l.size(head) - l.size(tail)
+		return strings.TrimRight(head, "\n") + fmt.Sprintf(marker, omitted) + strings.TrimLeft(tail, "\n")
+	}
+	return s[:l.headEnd(s, n)]
+}
+
+// headEnd returns the end of the longest prefix of s within n units,
+// moved back to a line end when one is in its second half.
+func (l Limit) headEnd(s string, n int) int {
+	offsets := runeOffsets(s)
+	i := sort.Search(len(offsets), func(i int) bool { return l.size(s[:offsets[i]]) > n })
+	end := offsets[i-1]
+	if nl := strings.LastIndexByte(s[:end], '\n'); end < len(s) && nl >= end/2 {
+		return nl + 1
+	}
+	return end
+}
+
+// tailStart returns the start of the longest suffix of s within n units,
+// moved forward to a line start when it is mid-line and one is in its first
+// half.
+func (l Limit) tailStart(s string, n int) int {
+	offsets := runeOffsets(s)
+	i := sort.Search(len(offsets), func(i int) bool { return l.size(s[offsets[i]:]) <= n })
+	start := offsets[i]
+	if start == 0 || s[start-1] == '\n' {
+		return start
+	}
+	if nl := strings.IndexByte(s[start:], '\n'); nl >= 0 && nl < (len(s)-start)/2 {
+		return start + nl + 1
+	}
+	return start
+}
+
+// runeOffsets returns the byte offset of every rune of s, and len(s).
+func runeOffsets(s string) []int {
+	offsets := make([]int, 0, len(s)+1)
+	for i := range s {
+		offsets = append(offsets, i)
+	}
+	return append(offsets, len(s))
+}
+
+// sections keeps the Markdown sections whose heading matches one of the
+// patterns, case-insensitively with * and ? wildcards. A section runs to the
+// next heading of the same or a higher level.
+func sections(s string, patterns []string) string {
+	var kept []string
+	keepLevel := 0
+	inFence := false
+	for _, line := range strings.SplitAfter(s, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if level, title := heading(trimmed); level > 0 && !inFence {
+			if keepLevel > 0 && level <= keepLevel {
+				keepLevel = 0
+			}
+			if keepLevel == 0 && matchesHeading(title, patterns) {
+				keepLevel = level
+			}
+		}
+		if keepLevel > 0 {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "")
+}
+
+func heading(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(strings.TrimRight(line[level:], "#"))
+}
+
+func matchesHeading(title string, patterns []string) bool {
+	title = strings.ToLower(title)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(strings.TrimSpace(pattern)), title); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package docs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+func TestLimitWith(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    Limit
+		spec    prompt.DocLimit
+		want    Limit
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			spec: prompt.DocLimit{Max: 100},
+			want: Limit{Max: 100, Unit: UnitBytes, Strategy: StrategyHead},
+		},
+		{
+			name: "max keeps the global unit",
+			base: Limit{Max: 500, Unit: UnitTokens, Strategy: StrategyTail},
+			spec: prompt.DocLimit{Max: 100},
+			want: Limit{Max: 100, Unit: UnitTokens, Strategy: StrategyTail},
+		},
+		{
+			name: "unit and strategy replace the global ones",
+			base: Limit{Max: 500, Unit: UnitTokens, Strategy: StrategyTail},
+			spec: prompt.DocLimit{Unit: "Bytes", Strategy: "head_tail"},
+			want: Limit{Max: 500, Unit: UnitBytes, Strategy: StrategyHeadTail},
+		},
+		{
+			name: "sections",
+			spec: prompt.DocLimit{Strategy: "sections", Sections: []string{"usage"}},
+			want: Limit{Unit: UnitBytes, Strategy: StrategySections, Sections: []string{"usage"}},
+		},
+		{name: "negative max", spec: prompt.DocLimit{Max: -1}, wantErr: true},
+		{name: "unknown unit", spec: prompt.DocLimit{Unit: "lines"}, wantErr: true},
+		{name: "unknown strategy", spec: prompt.DocLimit{Strategy: "middle"}, wantErr: true},
+		{name: "sections without a list", spec: prompt.DocLimit{Strategy: "sections"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.base.With(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("With(%+v) = %+v, want an error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("With(%+v) error: %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("With(%+v) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitApply(t *testing.T) {
+	lines := "line one\nline two\nline three\n"
+	markdown := "# Intro\nhi\n## Usage\nrun it\n### Flags\n-x\n## Other\nno\n"
+
+	tests := []struct {
+		name  string
+		limit Limit
+		files []prompt.File
+		want  []string
+		cuts  int
+	}{
+		{
+			name:  "under the limit",
+			limit: Limit{Max: 100, Unit: UnitBytes, Strategy: StrategyHead},
+			files: []prompt.File{{Path: "a", Content: lines}},
+			want:  []string{lines},
+		},
+		{
+			name:  "head stops at a line end",
+			limit: Limit{Max: 20, Unit: UnitBytes, Strategy: StrategyHead},
+			files: []prompt.File{{Path: "a", Content: lines}},
+			want:  []string{"line one\nline two\n"},
+			cuts:  1,
+		},
+		{
+			name:  "tail starts at a line start",
+			limit: Limit{Max: 20, Unit: UnitBytes, Strategy: StrategyTail},
+			files: []prompt.File{{Path: "a", Content: lines}},
+			want:  []string{"line two\nline three\n"},
+			cuts:  1,
+		},
+		{
+			name:  "tail moves past a partial line",
+			limit: Limit{Max: 16, Unit: UnitBytes, Strategy: StrategyTail},
+			files: []prompt.File{{Path: "a", Content: lines}},
+			want:  []string{"line three\n"},
+			cuts:  1,
+		},
+		{
+			name:  "later docs are left out once the limit is used up",
+			limit: Limit{Max: 11, Unit: UnitBytes, Strategy: StrategyHead},
+			files: []prompt.File{{Path: "a", Content: "0123456789\n"}, {Path: "b", Content: "abcdef\n"}},
+			want:  []string{"0123456789\n"},
+			cuts:  1,
+		},
+		{
+			name:  "sections",
+			limit: Limit{Unit: UnitBytes, Strategy: StrategySections, Sections: []string{"USAGE"}},
+			files: []prompt.File{{Path: "a", Content: markdown}, {Path: "b", Content: "```\n# Usage\n```\n"}},
+			want:  []string{"## Usage\nrun it\n### Flags\n-x\n"},
+			cuts:  2,
+		},
+		{
+			name:  "sections with wildcards",
+			limit: Limit{Unit: UnitBytes, Strategy: StrategySections, Sections: []string{"f*", "oth?r"}},
+			files: []prompt.File{{Path: "a", Content: markdown}},
+			want:  []string{"### Flags\n-x\n## Other\nno\n"},
+			cuts:  1,
+		},
+		{
+			name:  "no limit",
+			limit: Limit{Unit: UnitBytes, Strategy: StrategyHead},
+			files: []prompt.File{{Path: "a", Content: lines}},
+			want:  []string{lines},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, cuts := tt.limit.Apply(tt.files)
+			var got []string
+			for _, f := range files {
+				got = append(got, f.Content)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+			if len(cuts) != tt.cuts {
+				t.Errorf("Apply() reported %d cuts, want %d: %v", len(cuts), tt.cuts, cuts)
+			}
+		})
+	}
+}
+
+func TestLimitApplyStaysWithinMax(t *testing.T) {
+	doc := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40) + "\n" +
+		strings.Repeat("Ünïcödé wörds and 日本語 text.\n", 20)
+
+	for _, unit := range []Unit{UnitBytes, UnitTokens} {
+		for _, strategy := range []Strategy{StrategyHead, StrategyTail, StrategyHeadTail} {
+			limit := Limit{Max: 120, Unit: unit, Strategy: strategy}
+			files, cuts := limit.Apply([]prompt.File{{Path: "a", Content: doc}})
+			if len(files) != 1 || len(cuts) != 1 {
+				t.Fatalf("%s/%s: got %d files and %d cuts, want 1 and 1", unit, strategy, len(files), len(cuts))
+			}
+			if size := limit.size(files[0].Content); size > limit.Max || size == 0 {
+				t.Errorf("%s/%s: cut to %d, want 1 to %d", unit, strategy, size, limit.Max)
+			}
+			if cuts[0].To != limit.size(files[0].Content) {
+				t.Errorf("%s/%s: cut reports %d, content is %d", unit, strategy, cuts[0].To, limit.size(files[0].Content))
+			}
+			if strategy == StrategyHeadTail && !strings.Contains(files[0].Content, "omitted") {
+				t.Errorf("%s/%s: no elision marker in %q", unit, strategy, files[0].Content)
+			}
+		}
+	}
+}
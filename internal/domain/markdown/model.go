@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+type Formatter struct{}
+
+func NewFormatter() *Formatter {
+	return &Formatter{}
+}
+
+func (f *Formatter) FormatAsMarkdown(p prompt.Prompt) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("# " + p.Title + "\n\n")
+	if len(p.Tags) > 0 {
+		b.WriteString("**Tags:** " + strings.Join(p.Tags, ", ") + "\n\n")
+	}
+	if description := strings.TrimSpace(p.Description); description != "" {
+		b.WriteString(description + "\n\n")
+	}
+	b.WriteString(strings.TrimRight(p.Content, "\n") + "\n")
+
+	if len(p.Files) > 0 {
+		b.WriteString("\n## Files\n")
+		for _, file := range p.Files {
+			b.WriteString("\n### `" + file.Path + "`\n\n")
+			b.WriteString(fenced(file.Content, language(file.Path)))
+		}
+	}
+
+	return b.String(), nil
+}
+
+func (f *Formatter) FormatWithDoc(p prompt.Prompt) (string, error) {
+	baseOutput, err := f.FormatAsMarkdown(p)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(p.DocFiles) {
+	case 0:
+		return baseOutput, nil
+	case 1:
+		return baseOutput + "\n## Documentation\n\n" + strings.TrimRight(p.DocFiles[0].Content, "\n") + "\n", nil
+	}
+
+	var b strings.Builder
+	b.WriteString(baseOutput + "\n## Documentation\n")
+	for _, doc := range p.DocFiles {
+		b.WriteString("\n### " + doc.Path + "\n\n" + strings.TrimRight(doc.Content, "\n") + "\n")
+	}
+	return b.String(), nil
+}
+
+// fenced wraps content in a code fence longer than any backtick run it
+// contains, so embedded fences cannot close it early.
+func fenced(content, lang string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + lang + "\n" + strings.TrimRight(content, "\n") + "\n" + fence + "\n"
+}
+
+func language(path string) string {
+	return strings.TrimPrefix(filepath.Ext(path), ".")
+}
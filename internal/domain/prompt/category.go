@@ -0,0 +1,86 @@
+package prompt
+
+import (
+	"sort"
+	"strings"
+)
+
+// CategorySeparator separates the folders of a category path such as
+// "engineering/review/go".
+const CategorySeparator = "/"
+
+// CategoryNode is a folder of the category tree. Count includes the prompts
+// of every subfolder.
+type CategoryNode struct {
+	Name     string
+	Path     string
+	Count    int
+	Children []*CategoryNode
+}
+
+// CleanCategory normalizes a category path: folders are trimmed of spaces
+// and empty folders are dropped.
+func CleanCategory(path string) string {
+	var folders []string
+	for _, folder := range strings.Split(path, CategorySeparator) {
+		if folder = strings.TrimSpace(folder); folder != "" {
+			folders = append(folders, folder)
+		}
+	}
+	return strings.Join(folders, CategorySeparator)
+}
+
+// InCategory reports whether the prompt is in the folder or one of its
+// subfolders. Every prompt is in the root folder "".
+func (p Prompt) InCategory(path string) bool {
+	if path == "" {
+		return true
+	}
+	category := CleanCategory(p.Category)
+	return category == path || strings.HasPrefix(category, path+CategorySeparator)
+}
+
+// CategoryTree returns the root folder of the collection with its subfolders
+// sorted by name.
+func (pc PromptCollection) CategoryTree() *CategoryNode {
+	root := &CategoryNode{}
+	for _, p := range pc.Prompts {
+		root.Count++
+		category := CleanCategory(p.Category)
+		if category == "" {
+			continue
+		}
+
+		node := root
+		for _, folder := range strings.Split(category, CategorySeparator) {
+			node = node.child(folder)
+			node.Count++
+		}
+	}
+	root.sort()
+	return root
+}
+
+func (n *CategoryNode) child(name string) *CategoryNode {
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	path := name
+	if n.Path != "" {
+		path = n.Path + CategorySeparator + name
+	}
+	c := &CategoryNode{Name: name, Path: path}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+func (n *CategoryNode) sort() {
+	sort.Slice(n.Children, func(i, j int) bool {
+		return strings.ToLower(n.Children[i].Name) < strings.ToLower(n.Children[j].Name)
+	})
+	for _, c := range n.Children {
+		c.sort()
+	}
+}
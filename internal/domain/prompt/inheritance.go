@@ -0,0 +1,157 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	visitPending = iota + 1
+	visitDone
+)
+
+// ResolveInheritance merges every prompt that declares `extends` with its
+// parent chain. Title, ID, Extends and Favorite stay the child's own; Description,
+// Category, docs and the doc limit are overridden when the child sets them;
+// Tags, Variables and Presets are the union of parent and child; Content is
+// the parent's content followed by the child's.
+func (s *Service) ResolveInheritance(collection *PromptCollection) error {
+	resolved := make([]Prompt, len(collection.Prompts))
+	state := make([]int, len(collection.Prompts))
+
+	var resolve func(i int, chain []string) error
+	resolve = func(i int, chain []string) error {
+		p := collection.Prompts[i]
+		chain = append(chain, p.Key())
+
+		switch state[i] {
+		case visitDone:
+			return nil
+		case visitPending:
+			return fmt.Errorf("inheritance cycle: %s", strings.Join(chain, " -> "))
+		}
+
+		if p.Extends == "" {
+			resolved[i] = p
+			state[i] = visitDone
+			return nil
+		}
+
+		parentIdx, ok := collection.indexOf(p.Extends)
+		if !ok {
+			return fmt.Errorf("prompt '%s' extends unknown prompt '%s'", p.Key(), p.Extends)
+		}
+
+		state[i] = visitPending
+		if err := resolve(parentIdx, chain); err != nil {
+			return err
+		}
+
+		resolved[i] = s.mergePrompts(resolved[parentIdx], p)
+		state[i] = visitDone
+		return nil
+	}
+
+	for i := range collection.Prompts {
+		if err := resolve(i, nil); err != nil {
+			return err
+		}
+	}
+
+	collection.Prompts = resolved
+	return nil
+}
+
+func (s *Service) mergePrompts(parent, child Prompt) Prompt {
+	merged := child
+
+	if merged.Description == "" {
+		merged.Description = parent.Description
+	}
+	if merged.Category == "" {
+		merged.Category = parent.Category
+	}
+	if !merged.HasDocs() {
+		merged.Doc, merged.Docs = parent.Doc, parent.Docs
+	}
+	if merged.DocLimit == nil {
+		merged.DocLimit = parent.DocLimit
+	}
+
+	merged.Tags = unionStrings(parent.Tags, child.Tags)
+	merged.Variables = unionVariables(parent.Variables, child.Variables)
+	merged.Presets = nil
+	for _, preset := range parent.Presets {
+		merged.SetPreset(preset)
+	}
+	for _, preset := range child.Presets {
+		merged.SetPreset(preset)
+	}
+
+	switch {
+	case strings.TrimSpace(child.Content) == "":
+		merged.Content = parent.Content
+	case strings.TrimSpace(parent.Content) != "":
+		merged.Content = strings.TrimRight(parent.Content, "\n") + "\n\n" + child.Content
+	}
+
+	return merged
+}
+
+// Children returns the prompts that directly extend the given prompt. Each
+// `extends` is resolved the way ResolveInheritance does, so a reference to
+// another prompt's ID is not shown under a prompt titled the same.
+func (pc PromptCollection) Children(p Prompt) []Prompt {
+	self := -1
+	for i, c := range pc.Prompts {
+		if c.ID == p.ID && c.Title == p.Title {
+			self = i
+			break
+		}
+	}
+	if self < 0 {
+		return nil
+	}
+
+	var children []Prompt
+	for _, c := range pc.Prompts {
+		if c.Extends == "" {
+			continue
+		}
+		if parent, ok := pc.indexOf(c.Extends); ok && parent == self {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+func (pc PromptCollection) indexOf(ref string) (int, bool) {
+	for i, p := range pc.Prompts {
+		if p.ID != "" && p.ID == ref {
+			return i, true
+		}
+	}
+	for i, p := range pc.Prompts {
+		if p.Title == ref {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func unionStrings(a, b []string) []string {
+	if len(a) == 0 {
+		return b
+	}
+	seen := make(map[string]bool, len(a)+len(b))
+	out := make([]string, 0, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, v := range list {
+			if !seen[v] {
+				seen[v] = true
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,192 @@
+package prompt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveInheritanceMerge(t *testing.T) {
+	limit := &DocLimit{Max: 100}
+
+	tests := []struct {
+		name   string
+		parent Prompt
+		child  Prompt
+		want   Prompt
+	}{
+		{
+			name:   "empty fields are inherited",
+			parent: Prompt{ID: "base", Title: "Base", Description: "d", Category: "c", Doc: "README.md", DocLimit: limit, Favorite: true},
+			child:  Prompt{Title: "Child", Extends: "base"},
+			want:   Prompt{Title: "Child", Extends: "base", Description: "d", Category: "c", Doc: "README.md", DocLimit: limit},
+		},
+		{
+			name:   "set fields override",
+			parent: Prompt{ID: "base", Title: "Base", Description: "d", Category: "c", Doc: "README.md"},
+			child:  Prompt{ID: "child", Title: "Child", Extends: "base", Description: "mine", Category: "own", Docs: []string{"docs/*.md"}, Favorite: true},
+			want:   Prompt{ID: "child", Title: "Child", Extends: "base", Description: "mine", Category: "own", Docs: []string{"docs/*.md"}, Favorite: true},
+		},
+		{
+			name: "tags and variables are unions",
+			parent: Prompt{
+				ID: "base", Title: "Base",
+				Tags:      []string{"a", "b"},
+				Variables: []Variable{{Name: "x"}, {Name: "y", Type: "text"}},
+			},
+			child: Prompt{
+				Title: "Child", Extends: "base",
+				Tags:      []string{"b", "c"},
+				Variables: []Variable{{Name: "y", Type: "list"}, {Name: "z"}},
+			},
+			want: Prompt{
+				Title: "Child", Extends: "base",
+				Tags:      []string{"a", "b", "c"},
+				Variables: []Variable{{Name: "x"}, {Name: "y", Type: "list"}, {Name: "z"}},
+			},
+		},
+		{
+			name: "child presets replace those of the same name",
+			parent: Prompt{ID: "base", Title: "Base", Presets: []Preset{
+				{Name: "fast", Values: map[string]string{"a": "1"}},
+				{Name: "slow", Values: map[string]string{"a": "2"}},
+			}},
+			child: Prompt{Title: "Child", Extends: "base", Presets: []Preset{
+				{Name: "slow", Values: map[string]string{"a": "3"}},
+				{Name: "new", Values: map[string]string{"b": "4"}},
+			}},
+			want: Prompt{Title: "Child", Extends: "base", Presets: []Preset{
+				{Name: "fast", Values: map[string]string{"a": "1"}},
+				{Name: "slow", Values: map[string]string{"a": "3"}},
+				{Name: "new", Values: map[string]string{"b": "4"}},
+			}},
+		},
+		{
+			name:   "content is appended to the parent's",
+			parent: Prompt{ID: "base", Title: "Base", Content: "Be brief.\n\n"},
+			child:  Prompt{Title: "Child", Extends: "base", Content: "Review {{{code}}}"},
+			want:   Prompt{Title: "Child", Extends: "base", Content: "Be brief.\n\nReview {{{code}}}"},
+		},
+		{
+			name:   "blank content takes the parent's",
+			parent: Prompt{ID: "base", Title: "Base", Content: "Be brief."},
+			child:  Prompt{Title: "Child", Extends: "base", Content: " \n"},
+			want:   Prompt{Title: "Child", Extends: "base", Content: "Be brief."},
+		},
+		{
+			name:   "blank parent content is skipped",
+			parent: Prompt{ID: "base", Title: "Base", Content: "\n"},
+			child:  Prompt{Title: "Child", Extends: "base", Content: "Review"},
+			want:   Prompt{Title: "Child", Extends: "base", Content: "Review"},
+		},
+		{
+			name:   "extends by title",
+			parent: Prompt{Title: "Base", Tags: []string{"a"}},
+			child:  Prompt{Title: "Child", Extends: "Base"},
+			want:   Prompt{Title: "Child", Extends: "Base", Tags: []string{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection := &PromptCollection{Prompts: []Prompt{tt.child, tt.parent}}
+			if err := NewService().ResolveInheritance(collection); err != nil {
+				t.Fatalf("ResolveInheritance() error: %v", err)
+			}
+			if got := collection.Prompts[0]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("merged = %+v, want %+v", got, tt.want)
+			}
+			if got := collection.Prompts[1]; !reflect.DeepEqual(got, tt.parent) {
+				t.Errorf("parent changed to %+v", got)
+			}
+		})
+	}
+}
+
+func TestResolveInheritanceChain(t *testing.T) {
+	collection := &PromptCollection{Prompts: []Prompt{
+		{ID: "c", Title: "C", Extends: "b", Tags: []string{"c"}, Content: "C"},
+		{ID: "b", Title: "B", Extends: "a", Tags: []string{"b"}, Content: "B", Description: "from b"},
+		{ID: "a", Title: "A", Tags: []string{"a"}, Content: "A", Description: "from a", Category: "base"},
+	}}
+	if err := NewService().ResolveInheritance(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Prompt{
+		ID: "c", Title: "C", Extends: "b",
+		Tags:        []string{"a", "b", "c"},
+		Content:     "A\n\nB\n\nC",
+		Description: "from b",
+		Category:    "base",
+	}
+	if got := collection.Prompts[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("resolved = %+v, want %+v", got, want)
+	}
+}
+
+func TestResolveInheritanceErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		prompts []Prompt
+		want    string
+	}{
+		{
+			name:    "cycle",
+			prompts: []Prompt{{ID: "a", Title: "A", Extends: "b"}, {ID: "b", Title: "B", Extends: "a"}},
+			want:    "inheritance cycle: a -> b -> a",
+		},
+		{
+			name:    "extends itself",
+			prompts: []Prompt{{Title: "A", Extends: "A"}},
+			want:    "inheritance cycle: A -> A",
+		},
+		{
+			name:    "unknown parent",
+			prompts: []Prompt{{ID: "a", Title: "A", Extends: "missing"}},
+			want:    "prompt 'a' extends unknown prompt 'missing'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewService().ResolveInheritance(&PromptCollection{Prompts: tt.prompts})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ResolveInheritance() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestChildren(t *testing.T) {
+	collection := PromptCollection{Prompts: []Prompt{
+		{ID: "review", Title: "Code review"},
+		{Title: "review"},
+		{Title: "Strict review", Extends: "review"},
+		{Title: "Quick review", Extends: "Code review"},
+		{Title: "Go review", Extends: "Strict review"},
+	}}
+
+	tests := []struct {
+		name   string
+		parent Prompt
+		want   []string
+	}{
+		{name: "by ID and by title", parent: collection.Prompts[0], want: []string{"Strict review", "Quick review"}},
+		{name: "title equal to another prompt's ID", parent: collection.Prompts[1]},
+		{name: "direct children only", parent: collection.Prompts[2], want: []string{"Go review"}},
+		{name: "leaf", parent: collection.Prompts[4]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range collection.Children(tt.parent) {
+				got = append(got, c.Title)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Children(%q) = %q, want %q", tt.parent.Title, got, tt.want)
+			}
+		})
+	}
+}
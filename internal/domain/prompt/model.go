@@ -1,29 +1,139 @@
 package prompt
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 )
 
 type Prompt struct {
-	Title       string   `yaml:"title"`
-	Tags        []string `yaml:"tags,omitempty"`
-	Description string   `yaml:"description,omitempty"`
-	Content     string   `yaml:"content"`
-	Variables   []string `yaml:"variables,omitempty"`
-	Doc         string   `yaml:"doc,omitempty"`
+	ID          string     `yaml:"id,omitempty"`
+	Title       string     `yaml:"title"`
+	Extends     string     `yaml:"extends,omitempty"`
+	Tags        []string   `yaml:"tags,omitempty"`
+	Category    string     `yaml:"category,omitempty"`
+	Favorite    bool       `yaml:"favorite,omitempty"`
+	Description string     `yaml:"description,omitempty"`
+	Content     string     `yaml:"content"`
+	Variables   []Variable `yaml:"variables,omitempty"`
+	Doc         string     `yaml:"doc,omitempty"`
+	Docs        []string   `yaml:"docs,omitempty"`
+	DocLimit    *DocLimit  `yaml:"doc_limit,omitempty"`
+	Presets     []Preset   `yaml:"presets,omitempty"`
+	Files       []File     `yaml:"-"`
+	DocFiles    []File     `yaml:"-"`
+	Source      string     `yaml:"-"`
+}
+
+// Preset is a named set of variable values saved with a prompt.
+type Preset struct {
+	Name   string            `yaml:"name"`
+	Values map[string]string `yaml:"values,omitempty"`
+}
+
+// DocLimit caps the size of a prompt's docs. Max is counted in Unit, bytes
+// or tokens; Strategy chooses what is kept: head, tail, head_tail or the
+// sections under the given headings.
+type DocLimit struct {
+	Max      int      `yaml:"max,omitempty"`
+	Unit     string   `yaml:"unit,omitempty"`
+	Strategy string   `yaml:"strategy,omitempty"`
+	Sections []string `yaml:"sections,omitempty"`
+}
+
+// Key identifies a prompt by its ID, falling back to its title.
+func (p Prompt) Key() string {
+	if p.ID != "" {
+		return p.ID
+	}
+	return p.Title
+}
+
+// DocPatterns returns the paths and globs of the prompt's docs as written:
+// doc first, then docs.
+func (p Prompt) DocPatterns() []string {
+	if p.Doc == "" {
+		return p.Docs
+	}
+	return append([]string{p.Doc}, p.Docs...)
+}
+
+// HasDocs reports whether the prompt declares any doc.
+func (p Prompt) HasDocs() bool {
+	return p.Doc != "" || len(p.Docs) > 0
+}
+
+// Matches reports whether ref refers to this prompt by ID or title.
+func (p Prompt) Matches(ref string) bool {
+	return (p.ID != "" && p.ID == ref) || p.Title == ref
+}
+
+func (p Prompt) Preset(name string) (Preset, bool) {
+	for _, preset := range p.Presets {
+		if preset.Name == name {
+			return preset, true
+		}
+	}
+	return Preset{}, false
+}
+
+// SetPreset adds a preset, replacing any existing one with the same name.
+func (p *Prompt) SetPreset(preset Preset) {
+	for i := range p.Presets {
+		if p.Presets[i].Name == preset.Name {
+			p.Presets[i] = preset
+			return
+		}
+	}
+	p.Presets = append(p.Presets, preset)
+}
+
+func (p Prompt) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// RenameTag replaces the tag from with to, dropping the duplicate when the
+// prompt already has both. It reports whether the prompt had the tag.
+func (p *Prompt) RenameTag(from, to string) bool {
+	if !p.HasTag(from) {
+		return false
+	}
+	seen := make(map[string]bool, len(p.Tags))
+	tags := make([]string, 0, len(p.Tags))
+	for _, t := range p.Tags {
+		if t == from {
+			t = to
+		}
+		if !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	p.Tags = tags
+	return true
 }
 
 type PromptCollection struct {
 	Prompts []Prompt `yaml:"prompts"`
 }
 
+// Item is a prompt in the list. Index is its position in the collection it
+// came from, which tells apart prompts sharing a key.
 type Item struct {
 	Prompt
+	Index int
 }
 
 func (i Item) Title() string {
+	if i.Favorite {
+		return i.Prompt.Title + " ★"
+	}
 	return i.Prompt.Title
 }
 
@@ -32,24 +142,62 @@ func (i Item) Description() string {
 }
 
 func (i Item) FilterValue() string {
-	var builder strings.Builder
-	builder.WriteString(i.Prompt.Title)
+	return i.Prompt.Title
+}
 
-	if len(i.Tags) > 0 {
-		builder.WriteString(" ")
-		builder.WriteString(strings.Join(i.Tags, " "))
-	}
-	return builder.String()
+// SearchFields returns the fields the list filter ranks, most important
+// first: title, tags and description.
+func (i Item) SearchFields() []string {
+	description := strings.Join(strings.Fields(i.Prompt.Description), " ")
+	return []string{i.Prompt.Title, strings.Join(i.Tags, " "), description}
 }
 
 func (pc PromptCollection) ToItems() []list.Item {
 	items := make([]list.Item, len(pc.Prompts))
 	for i, p := range pc.Prompts {
-		items[i] = Item{Prompt: p}
+		items[i] = Item{Prompt: p, Index: i}
 	}
 	return items
 }
 
+// TagCount is a tag with the number of prompts carrying it.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// TagCounts returns every tag in the collection with its count, by name
+// ignoring case, then by name.
+func (pc PromptCollection) TagCounts() []TagCount {
+	counts := make(map[string]int)
+	for _, p := range pc.Prompts {
+		for _, tag := range p.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		a, b := strings.ToLower(tags[i].Tag), strings.ToLower(tags[j].Tag)
+		if a != b {
+			return a < b
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags
+}
+
+// Find looks a prompt up by ID or title.
+func (pc PromptCollection) Find(ref string) (Prompt, bool) {
+	if i, ok := pc.indexOf(ref); ok {
+		return pc.Prompts[i], true
+	}
+	return Prompt{}, false
+}
+
 func (pc PromptCollection) GetPromptByTitle(title string) (Prompt, bool) {
 	for _, p := range pc.Prompts {
 		if p.Title == title {
@@ -39,13 +39,25 @@ func (s *Service) ParseTags(tagString string) []string {
 	return cleanedTags
 }
 
-func (s *Service) ReplaceVariables(content string, vars map[string]string) string {
-	output := content
-	for k, v := range vars {
-		placeholder := "{{{" + k + "}}}"
-		output = strings.ReplaceAll(output, placeholder, v)
+func (s *Service) ReplaceVariables(content string, vars map[string]string) (string, error) {
+	tmpl, err := ParseTemplate(content)
+	if err != nil {
+		return "", err
 	}
-	return output
+	return tmpl.Execute(vars), nil
+}
+
+func (s *Service) OptionalVariables(content string) map[string]bool {
+	tmpl, err := ParseTemplate(content)
+	if err != nil {
+		return nil
+	}
+	return tmpl.OptionalVariables()
+}
+
+func (s *Service) ValidateTemplate(content string) error {
+	_, err := ParseTemplate(content)
+	return err
 }
 
 func (s *Service) CreatePrompt(title, tagString, description, content string) Prompt {
@@ -0,0 +1,86 @@
+package prompt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type SortMode string
+
+const (
+	SortTitle    SortMode = "title"
+	SortRecent   SortMode = "recent"
+	SortMostUsed SortMode = "most-used"
+	SortPinned   SortMode = "pinned"
+)
+
+var sortModes = []SortMode{SortTitle, SortRecent, SortMostUsed, SortPinned}
+
+// Usage is how often a prompt was copied and when it was last copied.
+type Usage struct {
+	Count    int       `yaml:"count"`
+	LastUsed time.Time `yaml:"last_used"`
+}
+
+func ParseSortMode(name string) (SortMode, error) {
+	for _, mode := range sortModes {
+		if string(mode) == name {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sort mode '%s' (use title, recent, most-used or pinned)", name)
+}
+
+// Next returns the sort mode after m, wrapping around.
+func (m SortMode) Next() SortMode {
+	for i, mode := range sortModes {
+		if mode == m {
+			return sortModes[(i+1)%len(sortModes)]
+		}
+	}
+	return SortTitle
+}
+
+func (m SortMode) Label() string {
+	switch m {
+	case SortRecent:
+		return "recently used"
+	case SortMostUsed:
+		return "most used"
+	case SortPinned:
+		return "favorites first"
+	default:
+		return "title"
+	}
+}
+
+// SortItems orders list items by the mode, falling back to the title for
+// ties. Prompts never used sort after used ones in the recent and most-used
+// modes.
+func (s *Service) SortItems(items []Item, mode SortMode, usage func(key string) Usage) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].Prompt, items[j].Prompt
+		switch mode {
+		case SortRecent:
+			ua, ub := usage(a.Key()), usage(b.Key())
+			if !ua.LastUsed.Equal(ub.LastUsed) {
+				return ua.LastUsed.After(ub.LastUsed)
+			}
+		case SortMostUsed:
+			ua, ub := usage(a.Key()), usage(b.Key())
+			if ua.Count != ub.Count {
+				return ua.Count > ub.Count
+			}
+			if !ua.LastUsed.Equal(ub.LastUsed) {
+				return ua.LastUsed.After(ub.LastUsed)
+			}
+		case SortPinned:
+			if a.Favorite != b.Favorite {
+				return a.Favorite
+			}
+		}
+		return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+	})
+}
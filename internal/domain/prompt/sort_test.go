@@ -0,0 +1,63 @@
+package prompt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSortItems(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+	usage := map[string]Usage{
+		"gamma": {Count: 3, LastUsed: day(3)},
+		"b":     {Count: 3, LastUsed: day(2)},
+		"Alpha": {Count: 1, LastUsed: day(1)},
+	}
+	prompts := []Prompt{
+		{Title: "Echo"},
+		{ID: "b", Title: "beta", Favorite: true},
+		{Title: "gamma"},
+		{Title: "Delta", Favorite: true},
+		{Title: "Alpha"},
+	}
+
+	tests := []struct {
+		mode SortMode
+		want []string
+	}{
+		{mode: SortTitle, want: []string{"Alpha", "beta", "Delta", "Echo", "gamma"}},
+		{mode: SortRecent, want: []string{"gamma", "beta", "Alpha", "Delta", "Echo"}},
+		{mode: SortMostUsed, want: []string{"gamma", "beta", "Alpha", "Delta", "Echo"}},
+		{mode: SortPinned, want: []string{"beta", "Delta", "Alpha", "Echo", "gamma"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			items := make([]Item, len(prompts))
+			for i, p := range prompts {
+				items[i] = Item{Prompt: p, Index: i}
+			}
+			NewService().SortItems(items, tt.mode, func(key string) Usage { return usage[key] })
+
+			var got []string
+			for _, item := range items {
+				got = append(got, item.Prompt.Title)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortItems(%s) = %q, want %q", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortItemsMostUsedPrefersCount(t *testing.T) {
+	usage := map[string]Usage{
+		"often":  {Count: 5, LastUsed: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"lately": {Count: 1, LastUsed: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	items := []Item{{Prompt: Prompt{Title: "lately"}}, {Prompt: Prompt{Title: "often"}}}
+	NewService().SortItems(items, SortMostUsed, func(key string) Usage { return usage[key] })
+	if items[0].Prompt.Title != "often" {
+		t.Errorf("SortItems(most-used) put %q first, want the most copied", items[0].Prompt.Title)
+	}
+}
@@ -0,0 +1,299 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	blockIf     = "if"
+	blockUnless = "unless"
+	blockEach   = "each"
+
+	// EachItemVariable is the placeholder bound to the current element inside
+	// an {{#each}} block.
+	EachItemVariable = "this"
+)
+
+// ParseError describes a malformed template with a 1-based position.
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("template error at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+type templateNode interface{}
+
+type textNode string
+
+type variableNode struct {
+	name string
+}
+
+type blockNode struct {
+	kind     string
+	name     string
+	body     []templateNode
+	elseBody []templateNode
+	inElse   bool
+	offset   int
+}
+
+// Template is a parsed prompt content supporting {{{var}}} placeholders and
+// {{#if var}}, {{#unless var}} and {{#each var}} blocks. Other {{...}} tags,
+// such as handlebars examples, are kept as text, and \{{ writes a literal {{.
+type Template struct {
+	nodes []templateNode
+}
+
+func ParseTemplate(content string) (*Template, error) {
+	root := &blockNode{}
+	stack := []*blockNode{root}
+	pos := 0
+
+	appendNode := func(n templateNode) {
+		top := stack[len(stack)-1]
+		if top.inElse {
+			top.elseBody = append(top.elseBody, n)
+		} else {
+			top.body = append(top.body, n)
+		}
+	}
+
+	appendText := func(text string) {
+		if text != "" {
+			appendNode(textNode(text))
+		}
+	}
+
+	for pos < len(content) {
+		next := strings.Index(content[pos:], "{{")
+		if next < 0 {
+			appendText(content[pos:])
+			break
+		}
+		start := pos + next
+
+		if start > 0 && content[start-1] == '\\' {
+			appendText(content[pos:start-1] + "{{")
+			pos = start + 2
+			continue
+		}
+
+		if strings.HasPrefix(content[start:], "{{{") {
+			end := strings.Index(content[start+3:], "}}}")
+			if end < 0 {
+				appendText(content[pos:])
+				break
+			}
+			appendText(content[pos:start])
+			appendNode(variableNode{name: strings.TrimSpace(content[start+3 : start+3+end])})
+			pos = start + 3 + end + 3
+			continue
+		}
+
+		end := strings.Index(content[start:], "}}")
+		if end < 0 || !isBlockTag(strings.TrimSpace(content[start+2:start+end]), stack[len(stack)-1]) {
+			appendText(content[pos : start+2])
+			pos = start + 2
+			continue
+		}
+		tagEnd := start + end + 2
+		tag := strings.TrimSpace(content[start+2 : start+end])
+
+		text := content[pos:start]
+		if lineStart, lineEnd, ok := standaloneTag(content, start, tagEnd); ok {
+			text = content[pos:max(pos, lineStart)]
+			tagEnd = lineEnd
+		}
+		appendText(text)
+		pos = tagEnd
+
+		switch {
+		case strings.HasPrefix(tag, "#"):
+			fields := strings.Fields(tag[1:])
+			kind := fields[0]
+			if len(fields) != 2 {
+				return nil, newParseError(content, start, fmt.Sprintf("'{{#%s}}' expects exactly one variable name", kind))
+			}
+			block := &blockNode{kind: kind, name: fields[1], offset: start}
+			appendNode(block)
+			stack = append(stack, block)
+
+		case strings.HasPrefix(tag, "/"):
+			kind := strings.TrimSpace(tag[1:])
+			top := stack[len(stack)-1]
+			if top == root {
+				return nil, newParseError(content, start, fmt.Sprintf("'{{/%s}}' without a matching opening block", kind))
+			}
+			if kind != top.kind {
+				return nil, newParseError(content, start, fmt.Sprintf("'{{/%s}}' closes '{{#%s %s}}'; expected '{{/%s}}'", kind, top.kind, top.name, top.kind))
+			}
+			stack = stack[:len(stack)-1]
+
+		default:
+			top := stack[len(stack)-1]
+			if top.inElse {
+				return nil, newParseError(content, start, fmt.Sprintf("duplicate '{{else}}' in '{{#%s %s}}'", top.kind, top.name))
+			}
+			top.inElse = true
+		}
+	}
+
+	if len(stack) > 1 {
+		open := stack[len(stack)-1]
+		return nil, newParseError(content, open.offset, fmt.Sprintf("'{{#%s %s}}' is never closed", open.kind, open.name))
+	}
+
+	return &Template{nodes: root.body}, nil
+}
+
+// Execute renders the template. Placeholders without a value are left as-is.
+func (t *Template) Execute(vars map[string]string) string {
+	var out strings.Builder
+	executeNodes(&out, t.nodes, vars)
+	return out.String()
+}
+
+// OptionalVariables returns the variables the template can render without:
+// block conditions and placeholders that only appear inside conditional blocks.
+func (t *Template) OptionalVariables() map[string]bool {
+	optional := make(map[string]bool)
+	required := make(map[string]bool)
+	collectVariables(t.nodes, false, optional, required)
+	for name := range required {
+		delete(optional, name)
+	}
+	return optional
+}
+
+func executeNodes(out *strings.Builder, nodes []templateNode, vars map[string]string) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case textNode:
+			out.WriteString(string(n))
+		case variableNode:
+			if v, ok := vars[n.name]; ok {
+				out.WriteString(v)
+			} else {
+				out.WriteString("{{{" + n.name + "}}}")
+			}
+		case *blockNode:
+			executeBlock(out, n, vars)
+		}
+	}
+}
+
+func executeBlock(out *strings.Builder, b *blockNode, vars map[string]string) {
+	value := vars[b.name]
+	switch b.kind {
+	case blockIf, blockUnless:
+		truthy := strings.TrimSpace(value) != ""
+		if truthy == (b.kind == blockIf) {
+			executeNodes(out, b.body, vars)
+		} else {
+			executeNodes(out, b.elseBody, vars)
+		}
+	case blockEach:
+		items := SplitList(value)
+		if len(items) == 0 {
+			return
+		}
+		scope := make(map[string]string, len(vars)+1)
+		for k, v := range vars {
+			scope[k] = v
+		}
+		for _, item := range items {
+			scope[EachItemVariable] = item
+			executeNodes(out, b.body, scope)
+		}
+	}
+}
+
+func collectVariables(nodes []templateNode, conditional bool, optional, required map[string]bool) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case variableNode:
+			if n.name == EachItemVariable {
+				continue
+			}
+			if conditional {
+				optional[n.name] = true
+			} else {
+				required[n.name] = true
+			}
+		case *blockNode:
+			optional[n.name] = true
+			collectVariables(n.body, true, optional, required)
+			collectVariables(n.elseBody, true, optional, required)
+		}
+	}
+}
+
+// SplitList splits a value into list items, one per line when it spans
+// several lines and comma-separated otherwise.
+func SplitList(value string) []string {
+	sep := ","
+	if strings.Contains(value, "\n") {
+		sep = "\n"
+	}
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// isBlockTag reports whether tag, the text between {{ and }}, is one of the
+// template's own block tags given the innermost open block. Anything else is
+// left as text so prompts can quote other template languages.
+func isBlockTag(tag string, top *blockNode) bool {
+	switch {
+	case strings.HasPrefix(tag, "#"):
+		fields := strings.Fields(tag[1:])
+		return len(fields) > 0 && isBlockKind(fields[0])
+	case strings.HasPrefix(tag, "/"):
+		return isBlockKind(strings.TrimSpace(tag[1:]))
+	case tag == "else":
+		return top.kind == blockIf || top.kind == blockUnless
+	}
+	return false
+}
+
+func isBlockKind(kind string) bool {
+	return kind == blockIf || kind == blockUnless || kind == blockEach
+}
+
+// standaloneTag reports whether the tag spanning [start, end) is the only
+// thing on its line, returning the line bounds to drop so block tags do not
+// leave blank lines behind.
+func standaloneTag(content string, start, end int) (int, int, bool) {
+	lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+	if strings.TrimLeft(content[lineStart:start], " \t") != "" {
+		return 0, 0, false
+	}
+	lineEnd := strings.IndexByte(content[end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(content)
+	} else {
+		lineEnd = end + lineEnd + 1
+	}
+	if strings.TrimSpace(content[end:lineEnd]) != "" {
+		return 0, 0, false
+	}
+	return lineStart, lineEnd, true
+}
+
+func newParseError(content string, offset int, msg string) *ParseError {
+	before := content[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := utf8.RuneCountInString(before[strings.LastIndexByte(before, '\n')+1:]) + 1
+	return &ParseError{Line: line, Column: column, Msg: msg}
+}
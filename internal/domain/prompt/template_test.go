@@ -0,0 +1,177 @@
+package prompt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTemplateExecute(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		vars    map[string]string
+		want    string
+	}{
+		{
+			name:    "placeholder",
+			content: "Hello {{{name}}}!",
+			vars:    map[string]string{"name": "world"},
+			want:    "Hello world!",
+		},
+		{
+			name:    "missing placeholder is kept",
+			content: "Hello {{{name}}}!",
+			want:    "Hello {{{name}}}!",
+		},
+		{
+			name:    "if true",
+			content: "a{{#if x}}b{{/if}}c",
+			vars:    map[string]string{"x": "1"},
+			want:    "abc",
+		},
+		{
+			name:    "if blank",
+			content: "a{{#if x}}b{{/if}}c",
+			vars:    map[string]string{"x": "  "},
+			want:    "ac",
+		},
+		{
+			name:    "if else",
+			content: "{{#if x}}yes{{else}}no{{/if}}",
+			want:    "no",
+		},
+		{
+			name:    "unless else",
+			content: "{{#unless x}}empty{{else}}{{{x}}}{{/unless}}",
+			vars:    map[string]string{"x": "set"},
+			want:    "set",
+		},
+		{
+			name:    "each over commas",
+			content: "{{#each langs}}[{{{this}}}]{{/each}}",
+			vars:    map[string]string{"langs": "go, rust,,zig"},
+			want:    "[go][rust][zig]",
+		},
+		{
+			name:    "each over lines",
+			content: "{{#each langs}}- {{{this}}}\n{{/each}}",
+			vars:    map[string]string{"langs": "go, rust\nzig"},
+			want:    "- go, rust\n- zig\n",
+		},
+		{
+			name:    "nested blocks",
+			content: "{{#each items}}{{#if flag}}{{{this}}}{{else}}-{{/if}}{{/each}}",
+			vars:    map[string]string{"items": "a,b", "flag": "on"},
+			want:    "ab",
+		},
+		{
+			name:    "nested else belongs to the inner block",
+			content: "{{#if a}}{{#if b}}ab{{else}}a{{/if}}{{else}}none{{/if}}",
+			vars:    map[string]string{"a": "1"},
+			want:    "a",
+		},
+		{
+			name:    "standalone tags drop their lines",
+			content: "start\n{{#if x}}\nbody\n{{/if}}\nend",
+			vars:    map[string]string{"x": "1"},
+			want:    "start\nbody\nend",
+		},
+		{
+			name:    "unknown blocks are text",
+			content: "{{#items}}{{name}}{{/items}}",
+			want:    "{{#items}}{{name}}{{/items}}",
+		},
+		{
+			name:    "handlebars helpers are text",
+			content: "{{#with user}}\n{{> partial}}\n{{/with}}",
+			want:    "{{#with user}}\n{{> partial}}\n{{/with}}",
+		},
+		{
+			name:    "else outside a block is text",
+			content: "a {{else}} b",
+			want:    "a {{else}} b",
+		},
+		{
+			name:    "else inside each is text",
+			content: "{{#each xs}}{{{this}}}{{else}}{{/each}}",
+			vars:    map[string]string{"xs": "a,b"},
+			want:    "a{{else}}b{{else}}",
+		},
+		{
+			name:    "unterminated tag is text",
+			content: "use {{#if to open",
+			want:    "use {{#if to open",
+		},
+		{
+			name:    "escaped block",
+			content: `\{{#if x}}{{{x}}}\{{/if}}`,
+			vars:    map[string]string{"x": "v"},
+			want:    "{{#if x}}v{{/if}}",
+		},
+		{
+			name:    "escaped placeholder",
+			content: `\{{{x}}}`,
+			vars:    map[string]string{"x": "v"},
+			want:    "{{{x}}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.content)
+			if err != nil {
+				t.Fatalf("ParseTemplate(%q) error: %v", tt.content, err)
+			}
+			if got := tmpl.Execute(tt.vars); got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		column  int
+	}{
+		{name: "if without a name", content: "{{#if}}x{{/if}}", line: 1, column: 1},
+		{name: "each with two names", content: "ab\n  {{#each a b}}{{/each}}", line: 2, column: 3},
+		{name: "close without open", content: "x\n{{/if}}", line: 2, column: 1},
+		{name: "mismatched close", content: "{{#if a}}\n{{#each b}}\n{{/if}}", line: 3, column: 1},
+		{name: "duplicate else", content: "{{#if a}}1{{else}}2{{else}}3{{/if}}", line: 1, column: 20},
+		{name: "never closed", content: "one\ntwo {{#unless a}}", line: 2, column: 5},
+		{name: "column counts runes", content: "héllo {{#if a}}", line: 1, column: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate(tt.content)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseTemplate(%q) error = %v, want a *ParseError", tt.content, err)
+			}
+			if parseErr.Line != tt.line || parseErr.Column != tt.column {
+				t.Errorf("error at %d:%d, want %d:%d (%v)", parseErr.Line, parseErr.Column, tt.line, tt.column, err)
+			}
+		})
+	}
+}
+
+func TestOptionalVariables(t *testing.T) {
+	tmpl, err := ParseTemplate("{{{a}}}{{#if b}}{{{c}}}{{{a}}}{{/if}}{{#each d}}{{{this}}}{{/each}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := tmpl.OptionalVariables()
+	want := map[string]bool{"b": true, "c": true, "d": true}
+	if len(got) != len(want) {
+		t.Fatalf("OptionalVariables() = %v, want %v", got, want)
+	}
+	for name := range want {
+		if !got[name] {
+			t.Errorf("OptionalVariables() = %v, missing %s", got, name)
+		}
+	}
+}
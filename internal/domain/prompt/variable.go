@@ -0,0 +1,135 @@
+package prompt
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	VariableText    = "text"
+	VariableFile    = "file"
+	VariableCommand = "command"
+
+	SourceClipboard = "clipboard"
+)
+
+// Variable is a placeholder declared by a prompt. In YAML it is either a plain
+// name or a mapping with a name, a type and where its value is pre-filled from.
+// Command variables are filled with the output of Command at render time;
+// values of sensitive variables are never remembered.
+type Variable struct {
+	Name      string        `yaml:"name"`
+	Type      string        `yaml:"type,omitempty"`
+	Source    string        `yaml:"source,omitempty"`
+	Command   string        `yaml:"command,omitempty"`
+	Dir       string        `yaml:"dir,omitempty"`
+	Timeout   time.Duration `yaml:"timeout,omitempty"`
+	MaxOutput int           `yaml:"max_output,omitempty"`
+	Sensitive bool          `yaml:"sensitive,omitempty"`
+}
+
+type variableFields Variable
+
+func (v *Variable) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*v = Variable{Name: node.Value}
+		return nil
+	}
+
+	var fields variableFields
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+	if fields.Name == "" {
+		return fmt.Errorf("line %d: variable without a name", node.Line)
+	}
+	switch fields.Type {
+	case "", VariableText, VariableFile, VariableCommand:
+	default:
+		return fmt.Errorf("line %d: variable '%s' has unknown type '%s'", node.Line, fields.Name, fields.Type)
+	}
+	if fields.Type == VariableCommand && fields.Command == "" {
+		return fmt.Errorf("line %d: command variable '%s' has no command", node.Line, fields.Name)
+	}
+	switch fields.Source {
+	case "", SourceClipboard:
+	default:
+		return fmt.Errorf("line %d: variable '%s' has unknown source '%s'", node.Line, fields.Name, fields.Source)
+	}
+	*v = Variable(fields)
+	return nil
+}
+
+func (v Variable) MarshalYAML() (interface{}, error) {
+	if v == (Variable{Name: v.Name}) || v == (Variable{Name: v.Name, Type: VariableText}) {
+		return v.Name, nil
+	}
+	return variableFields(v), nil
+}
+
+func (v Variable) IsFile() bool {
+	return v.Type == VariableFile
+}
+
+func (v Variable) IsCommand() bool {
+	return v.Type == VariableCommand
+}
+
+func (v Variable) FromClipboard() bool {
+	return v.Source == SourceClipboard
+}
+
+// File is the content of a file injected into a rendered prompt.
+type File struct {
+	Path    string
+	Content string
+}
+
+func (p Prompt) VariableNames() []string {
+	names := make([]string, len(p.Variables))
+	for i, v := range p.Variables {
+		names[i] = v.Name
+	}
+	return names
+}
+
+func (p Prompt) Variable(name string) (Variable, bool) {
+	for _, v := range p.Variables {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variable{}, false
+}
+
+// Commands returns the command variables of the prompt.
+func (p Prompt) Commands() []Variable {
+	var commands []Variable
+	for _, v := range p.Variables {
+		if v.IsCommand() {
+			commands = append(commands, v)
+		}
+	}
+	return commands
+}
+
+func unionVariables(a, b []Variable) []Variable {
+	if len(a) == 0 {
+		return b
+	}
+	out := make([]Variable, 0, len(a)+len(b))
+	index := make(map[string]int, len(a)+len(b))
+	for _, list := range [][]Variable{a, b} {
+		for _, v := range list {
+			if i, ok := index[v.Name]; ok {
+				out[i] = v
+				continue
+			}
+			index[v.Name] = len(out)
+			out = append(out, v)
+		}
+	}
+	return out
+}
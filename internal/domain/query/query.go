@@ -0,0 +1,400 @@
+// Package query parses field-scoped search queries such as
+// `tag:go -tag:draft title:review "exact phrase"` into predicates over prompts.
+package query
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+// Fields lists the field names a term may be scoped to.
+var Fields = []string{"tag", "title", "desc", "content", "category", "id", "var", "preset", "extends", "has"}
+
+// HasValues lists the properties accepted by has:.
+var HasValues = []string{"doc", "vars", "presets", "tags", "id", "parent"}
+
+// SyntaxError describes a malformed query with a 1-based column.
+type SyntaxError struct {
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query error at column %d: %s", e.Column, e.Msg)
+}
+
+// Query is a parsed search query. Adjacent terms must all match; terms joined
+// by OR form a group of which one must match, and OR binds tighter than the
+// implicit AND, so `tag:go OR tag:rust review` means (go or rust) and review.
+type Query struct {
+	root       node
+	structured bool
+}
+
+type node interface {
+	match(p prompt.Prompt) bool
+}
+
+type andNode []node
+
+type orNode []node
+
+type notNode struct {
+	node node
+}
+
+type termNode struct {
+	field string
+	value string
+}
+
+// Parse parses a query. An empty query matches every prompt.
+func Parse(input string) (*Query, error) {
+	p := &parser{input: []rune(input)}
+	root, err := p.parseAnd(false)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{root: root, structured: p.structured}, nil
+}
+
+// Match reports whether the prompt satisfies the query.
+func (q *Query) Match(p prompt.Prompt) bool {
+	return q.root.match(p)
+}
+
+// Structured reports whether the query uses anything beyond plain words:
+// fields, negation, OR groups, parentheses or quoted phrases.
+func (q *Query) Structured() bool {
+	return q.structured
+}
+
+// Filter returns the prompts matching the query, keeping their order.
+func (q *Query) Filter(prompts []prompt.Prompt) []prompt.Prompt {
+	var matched []prompt.Prompt
+	for _, p := range prompts {
+		if q.Match(p) {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+func (n andNode) match(p prompt.Prompt) bool {
+	for _, child := range n {
+		if !child.match(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n orNode) match(p prompt.Prompt) bool {
+	for _, child := range n {
+		if child.match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n notNode) match(p prompt.Prompt) bool {
+	return !n.node.match(p)
+}
+
+func (n termNode) match(p prompt.Prompt) bool {
+	switch n.field {
+	case "":
+		return contains(p.Title, n.value) || contains(p.Description, n.value) || anyMatch(p.Tags, func(tag string) bool {
+			return contains(tag, n.value)
+		})
+	case "tag":
+		return anyMatch(p.Tags, func(tag string) bool { return glob(n.value, tag) })
+	case "title":
+		return contains(p.Title, n.value)
+	case "desc":
+		return contains(p.Description, n.value)
+	case "content":
+		return contains(p.Content, n.value)
+	case "category":
+		p.Category = strings.ToLower(p.Category)
+		return p.InCategory(prompt.CleanCategory(n.value))
+	case "id":
+		return glob(n.value, p.ID)
+	case "var":
+		return anyMatch(p.VariableNames(), func(name string) bool { return glob(n.value, name) })
+	case "preset":
+		return anyMatch(p.Presets, func(preset prompt.Preset) bool { return glob(n.value, preset.Name) })
+	case "extends":
+		return glob(n.value, p.Extends)
+	case "has":
+		switch n.value {
+		case "doc":
+			return p.HasDocs()
+		case "vars":
+			return len(p.Variables) > 0
+		case "presets":
+			return len(p.Presets) > 0
+		case "tags":
+			return len(p.Tags) > 0
+		case "id":
+			return p.ID != ""
+		case "parent":
+			return p.Extends != ""
+		}
+	}
+	return false
+}
+
+type parser struct {
+	input      []rune
+	pos        int
+	structured bool
+}
+
+// parseAnd parses terms up to the end of the input, or up to the closing
+// parenthesis of a group.
+func (p *parser) parseAnd(inGroup bool) (node, error) {
+	var terms andNode
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) {
+			if inGroup {
+				return nil, p.errorf(p.pos, "missing ')'")
+			}
+			break
+		}
+		if p.input[p.pos] == ')' {
+			if !inGroup {
+				return nil, p.errorf(p.pos, "unexpected ')'")
+			}
+			break
+		}
+
+		term, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	if inGroup && len(terms) == 0 {
+		return nil, p.errorf(p.pos, "empty group")
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	group := orNode{first}
+
+	for {
+		p.skipSpace()
+		start := p.pos
+		if !p.consumeOr() {
+			break
+		}
+		p.structured = true
+		p.skipSpace()
+		if p.pos >= len(p.input) || p.input[p.pos] == ')' {
+			return nil, p.errorf(start, "OR needs a term on both sides")
+		}
+		next, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, next)
+	}
+
+	if len(group) == 1 {
+		return first, nil
+	}
+	return group, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	start := p.pos
+	switch p.input[p.pos] {
+	case '-':
+		p.pos++
+		if p.pos >= len(p.input) || unicode.IsSpace(p.input[p.pos]) {
+			return nil, p.errorf(start, "'-' must be followed by a term")
+		}
+		p.structured = true
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node: inner}, nil
+	case '(':
+		p.pos++
+		p.structured = true
+		group, err := p.parseAnd(true)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+		return group, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *parser) parseTerm() (node, error) {
+	start := p.pos
+	if p.input[p.pos] == '"' {
+		phrase, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		p.structured = true
+		return termNode{value: phrase}, nil
+	}
+
+	word := p.readWord()
+	if word == "" {
+		// Only a '-' directly before ')' leaves the parser on an empty word.
+		return nil, p.errorf(start-1, "'-' must be followed by a term")
+	}
+	field, value, scoped := strings.Cut(word, ":")
+	if !scoped {
+		return termNode{value: strings.ToLower(word)}, nil
+	}
+
+	field = normalizeField(strings.ToLower(field))
+	if !isField(field) {
+		return nil, p.errorf(start, fmt.Sprintf("unknown field '%s' (fields: %s)", field, strings.Join(Fields, ", ")))
+	}
+	p.structured = true
+
+	if value == "" && p.pos < len(p.input) && p.input[p.pos] == '"' {
+		quoted, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		value = quoted
+	}
+	if value == "" {
+		return nil, p.errorf(start, fmt.Sprintf("missing value after '%s:'", field))
+	}
+
+	value = strings.ToLower(value)
+	if field == "has" && !isHasValue(value) {
+		return nil, p.errorf(start, fmt.Sprintf("unknown property 'has:%s' (use %s)", value, strings.Join(HasValues, ", ")))
+	}
+	return termNode{field: field, value: value}, nil
+}
+
+func (p *parser) parseQuoted() (string, error) {
+	start := p.pos
+	p.pos++
+	end := p.pos
+	for end < len(p.input) && p.input[end] != '"' {
+		end++
+	}
+	if end >= len(p.input) {
+		return "", p.errorf(start, "unterminated quote")
+	}
+	phrase := strings.ToLower(string(p.input[p.pos:end]))
+	p.pos = end + 1
+	if phrase == "" {
+		return "", p.errorf(start, "empty phrase")
+	}
+	return phrase, nil
+}
+
+func (p *parser) readWord() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		r := p.input[p.pos]
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+// consumeOr consumes an OR keyword (or |) standing as a word of its own.
+func (p *parser) consumeOr() bool {
+	rest := p.input[p.pos:]
+	if len(rest) >= 1 && rest[0] == '|' {
+		p.pos++
+		return true
+	}
+	if len(rest) >= 2 && string(rest[:2]) == "OR" && (len(rest) == 2 || unicode.IsSpace(rest[2]) || rest[2] == '(') {
+		p.pos += 2
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(pos int, msg string) error {
+	return &SyntaxError{Column: pos + 1, Msg: msg}
+}
+
+func normalizeField(field string) string {
+	switch field {
+	case "tags":
+		return "tag"
+	case "description":
+		return "desc"
+	case "vars", "variable":
+		return "var"
+	case "folder":
+		return "category"
+	}
+	return field
+}
+
+func isField(field string) bool {
+	for _, f := range Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func isHasValue(value string) bool {
+	for _, v := range HasValues {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), substr)
+}
+
+// glob matches case-insensitively, with * and ? wildcards.
+func glob(pattern, s string) bool {
+	ok, _ := path.Match(pattern, strings.ToLower(s))
+	return ok
+}
+
+func anyMatch[T any](values []T, match func(T) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
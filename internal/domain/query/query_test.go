@@ -0,0 +1,139 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+var library = []prompt.Prompt{
+	{
+		ID:          "go-review",
+		Title:       "Go Review",
+		Tags:        []string{"go", "review"},
+		Category:    "dev/backend",
+		Description: "Review Go code",
+		Content:     "Check {{{code}}}",
+		Variables:   []prompt.Variable{{Name: "code"}},
+		Presets:     []prompt.Preset{{Name: "strict"}},
+	},
+	{
+		Title:    "Rust Review",
+		Tags:     []string{"rust", "review"},
+		Category: "dev",
+		Extends:  "go-review",
+		Content:  "Check the borrow checker",
+	},
+	{
+		Title:       "Draft Idea",
+		Tags:        []string{"draft"},
+		Description: "Brainstorm",
+		Content:     "Write freely",
+	},
+}
+
+func TestQueryFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Go Review", "Rust Review", "Draft Idea"}},
+		{"review", []string{"Go Review", "Rust Review"}},
+		{"brainstorm", []string{"Draft Idea"}},
+		{"tag:go", []string{"Go Review"}},
+		{"TAGS:GO", []string{"Go Review"}},
+		{"tag:r*", []string{"Go Review", "Rust Review"}},
+		{"-tag:review", []string{"Draft Idea"}},
+		{"tag:go OR tag:rust", []string{"Go Review", "Rust Review"}},
+		{"tag:go | tag:draft", []string{"Go Review", "Draft Idea"}},
+		{"tag:rust OR tag:go review", []string{"Go Review", "Rust Review"}},
+		{"(tag:go OR tag:draft) idea", []string{"Draft Idea"}},
+		{`"go code"`, []string{"Go Review"}},
+		{`title:"rust review"`, []string{"Rust Review"}},
+		{"content:borrow", []string{"Rust Review"}},
+		{"category:dev", []string{"Go Review", "Rust Review"}},
+		{"folder:dev/backend", []string{"Go Review"}},
+		{"id:go-*", []string{"Go Review"}},
+		{"var:code", []string{"Go Review"}},
+		{"preset:strict", []string{"Go Review"}},
+		{"extends:go-review", []string{"Rust Review"}},
+		{"has:parent", []string{"Rust Review"}},
+		{"has:presets -has:parent", []string{"Go Review"}},
+		{"-has:tags", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.query, err)
+			}
+			var got []string
+			for _, p := range q.Filter(library) {
+				got = append(got, p.Title)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q).Filter() = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query  string
+		column int
+	}{
+		{"tag:", 1},
+		{"review foo:bar", 8},
+		{"has:kids", 1},
+		{"review )", 8},
+		{"(tag:go", 8},
+		{"()", 2},
+		{`say "open`, 5},
+		{`""`, 1},
+		{"go OR", 4},
+		{"- go", 1},
+		{"(go -)", 5},
+		{"source:team", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want a *SyntaxError", tt.query, err)
+			}
+			if syntaxErr.Column != tt.column {
+				t.Errorf("Parse(%q) error at column %d, want %d (%v)", tt.query, syntaxErr.Column, tt.column, err)
+			}
+		})
+	}
+}
+
+func TestQueryStructured(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"go review", false},
+		{"tag:go", true},
+		{"-go", true},
+		{`"go"`, true},
+		{"go OR rust", true},
+		{"(go)", true},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.query, err)
+		}
+		if got := q.Structured(); got != tt.want {
+			t.Errorf("Parse(%q).Structured() = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/renatogalera/promptgen/internal/domain/docs"
+	"github.com/renatogalera/promptgen/internal/domain/markdown"
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/resolver"
+	"github.com/renatogalera/promptgen/internal/domain/xml"
+	"github.com/renatogalera/promptgen/pkg/fileset"
+	"github.com/renatogalera/promptgen/pkg/shell"
+)
+
+type Format string
+
+const (
+	FormatXML      Format = "xml"
+	FormatMarkdown Format = "markdown"
+)
+
+var formats = []Format{FormatXML, FormatMarkdown}
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "xml":
+		return FormatXML, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown output format '%s' (expected xml or markdown)", s)
+}
+
+func (f Format) Label() string {
+	if f == FormatMarkdown {
+		return "Markdown"
+	}
+	return "XML"
+}
+
+// Extension is the file extension used when saving output in the format.
+func (f Format) Extension() string {
+	if f == FormatMarkdown {
+		return "md"
+	}
+	return "xml"
+}
+
+func (f Format) Next() Format {
+	for i, format := range formats {
+		if format == f {
+			return formats[(i+1)%len(formats)]
+		}
+	}
+	return FormatXML
+}
+
+// Result is a rendered prompt together with anything worth telling the user
+// about how it was produced, such as skipped files.
+type Result struct {
+	Prompt prompt.Prompt
+	Output string
+	Notes  []string
+}
+
+// Renderer turns a prompt and the user's variable values into final output:
+// built-ins are resolved, file variables read, the template executed and the
+// result formatted. Command variables only run for trusted sources or after
+// the user approved them.
+type Renderer struct {
+	service  *prompt.Service
+	resolver *resolver.Resolver
+	xml      *xml.Formatter
+	markdown *markdown.Formatter
+	files    fileset.Options
+	docs     docs.Policy
+	docLimit docs.Limit
+
+	mu       sync.Mutex
+	trusted  []string
+	approved map[string]bool
+	docCuts  map[string]docCut
+}
+
+// docCut is the last cut of the docs of a prompt, reused while the limit and
+// the docs stay the same: estimating tokens for every cut point is slow on
+// large docs.
+type docCut struct {
+	sum   string
+	files []prompt.File
+	notes []string
+}
+
+func New(service *prompt.Service, res *resolver.Resolver, xmlFormatter *xml.Formatter, markdownFormatter *markdown.Formatter) *Renderer {
+	return &Renderer{
+		service:  service,
+		resolver: res,
+		xml:      xmlFormatter,
+		markdown: markdownFormatter,
+		files:    fileset.DefaultOptions(),
+		docs:     docs.PolicyError,
+		approved: make(map[string]bool),
+		docCuts:  make(map[string]docCut),
+	}
+}
+
+// SetMissingDocs sets what happens when a doc of the prompt cannot be read.
+func (r *Renderer) SetMissingDocs(policy docs.Policy) {
+	r.docs = policy
+}
+
+// SetDocLimit sets the limit applied to the docs of prompts without one of
+// their own.
+func (r *Renderer) SetDocLimit(limit docs.Limit) {
+	r.docLimit = limit
+}
+
+// Docs reads the docs attached to the prompt under the missing-doc policy.
+func (r *Renderer) Docs(p prompt.Prompt) ([]prompt.File, []string, error) {
+	return docs.Load(p, r.docs)
+}
+
+// Prepare fills the prompt content without formatting it.
+func (r *Renderer) Prepare(p prompt.Prompt, vars map[string]string) (prompt.Prompt, []string, error) {
+	values, err := r.resolver.Values(p.Content)
+	if err != nil {
+		return p, nil, err
+	}
+	for k, v := range vars {
+		values[k] = v
+	}
+
+	if err := r.untrusted(p); err != nil {
+		return p, nil, err
+	}
+
+	var notes []string
+	for _, v := range Commands(p) {
+		output, commandNotes, err := r.runCommand(v)
+		notes = append(notes, commandNotes...)
+		if err != nil {
+			return p, notes, fmt.Errorf("variable '%s': %w", v.Name, err)
+		}
+		values[v.Name] = output
+	}
+
+	for _, v := range p.Variables {
+		if !v.IsFile() || strings.TrimSpace(values[v.Name]) == "" {
+			continue
+		}
+		var paths []string
+		for _, pattern := range prompt.SplitList(values[v.Name]) {
+			files, fileNotes, err := fileset.Collect(pattern, r.files)
+			notes = append(notes, fileNotes...)
+			if err != nil {
+				return p, notes, fmt.Errorf("variable '%s': %w", v.Name, err)
+			}
+			for _, f := range files {
+				p.Files = append(p.Files, prompt.File{Path: f.Path, Content: f.Content})
+				paths = append(paths, f.Path)
+			}
+		}
+		values[v.Name] = strings.Join(paths, ", ")
+	}
+
+	content, err := r.service.ReplaceVariables(p.Content, values)
+	if err != nil {
+		return p, notes, err
+	}
+	p.Content = content
+	return p, notes, nil
+}
+
+func (r *Renderer) runCommand(v prompt.Variable) (string, []string, error) {
+	result, err := shell.Run(v.Command, shell.Options{Dir: v.Dir, Timeout: v.Timeout, MaxOutput: v.MaxOutput})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var notes []string
+	switch {
+	case result.TimedOut:
+		notes = append(notes, fmt.Sprintf("command for '%s' timed out", v.Name))
+	case result.ExitCode != 0:
+		notes = append(notes, fmt.Sprintf("command for '%s' exited with status %d", v.Name, result.ExitCode))
+	}
+	if result.Truncated {
+		notes = append(notes, fmt.Sprintf("output of '%s' was truncated", v.Name))
+	}
+	return strings.TrimRight(result.Output, "\n"), notes, nil
+}
+
+func (r *Renderer) Render(p prompt.Prompt, vars map[string]string, format Format) (Result, error) {
+	prepared, notes, err := r.Prepare(p, vars)
+	if err != nil {
+		return Result{Notes: notes}, err
+	}
+	var docNotes []string
+	prepared.DocFiles, docNotes, err = r.loadDocs(prepared)
+	notes = append(notes, docNotes...)
+	if err != nil {
+		return Result{Prompt: prepared, Notes: notes}, err
+	}
+
+	output, err := r.Format(prepared, format)
+	if err != nil {
+		return Result{Prompt: prepared, Notes: notes}, err
+	}
+
+	return Result{Prompt: prepared, Output: output, Notes: notes}, nil
+}
+
+// Preview renders p without side effects, cheap enough to run on every
+// keystroke: built-ins are expected in vars, files and docs are not read and
+// commands are not run. The docs shown are p.DocFiles, as loaded once with
+// PreviewDocs. Placeholders without a value are left in place.
+func (r *Renderer) Preview(p prompt.Prompt, vars map[string]string, format Format) (string, error) {
+	values := make(map[string]string, len(vars))
+	for k, v := range vars {
+		values[k] = v
+	}
+	for _, v := range p.Commands() {
+		values[v.Name] = "[output of: " + v.Command + "]"
+	}
+
+	content, err := r.service.ReplaceVariables(p.Content, values)
+	if err != nil {
+		return "", err
+	}
+	p.Content = content
+	return r.Format(p, format)
+}
+
+// PreviewDocs reads the docs of the prompt cut to the doc limit, as Render
+// attaches them, for Preview.
+func (r *Renderer) PreviewDocs(p prompt.Prompt) ([]prompt.File, []string, error) {
+	return r.loadDocs(p)
+}
+
+// loadDocs reads the docs of the prompt and cuts them to the doc limit, the
+// prompt's own overriding the global one, with a note for each doc cut.
+func (r *Renderer) loadDocs(p prompt.Prompt) ([]prompt.File, []string, error) {
+	files, notes, err := docs.Load(p, r.docs)
+	if err != nil {
+		return nil, notes, err
+	}
+
+	limit := r.docLimit
+	if p.DocLimit != nil {
+		if limit, err = limit.With(*p.DocLimit); err != nil {
+			return nil, notes, fmt.Errorf("invalid doc_limit of '%s': %w", p.Title, err)
+		}
+	}
+	sum := docsSum(files, limit)
+	r.mu.Lock()
+	cached, ok := r.docCuts[p.Key()]
+	r.mu.Unlock()
+	if ok && cached.sum == sum {
+		return cached.files, append(notes, cached.notes...), nil
+	}
+
+	files, cuts := limit.Apply(files)
+	var cutNotes []string
+	for _, cut := range cuts {
+		cutNotes = append(cutNotes, cut.String())
+	}
+	r.mu.Lock()
+	r.docCuts[p.Key()] = docCut{sum: sum, files: files, notes: cutNotes}
+	r.mu.Unlock()
+	return files, append(notes, cutNotes...), nil
+}
+
+// docsSum identifies docs and the limit they are cut to.
+func docsSum(files []prompt.File, limit docs.Limit) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00%s\x00%s\x00%q\x00%s\x00", limit.Max, limit.Unit, limit.Strategy, limit.Sections, limit.Family.Name)
+	for _, f := range files {
+		fmt.Fprintf(h, "%s\x00%d\x00", f.Path, len(f.Content))
+		h.Write([]byte(f.Content))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (r *Renderer) Format(p prompt.Prompt, format Format) (string, error) {
+	if format == FormatMarkdown {
+		return r.markdown.FormatWithDoc(p)
+	}
+	return r.xml.FormatWithDoc(p)
+}
@@ -0,0 +1,100 @@
+package render
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/renatogalera/promptgen/internal/domain/docs"
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+// UntrustedError is returned when a prompt would run commands but comes from
+// a source the user has not trusted and the commands were not approved.
+type UntrustedError struct {
+	Source   string
+	Commands []string
+}
+
+func (e *UntrustedError) Error() string {
+	return fmt.Sprintf("refusing to run commands from untrusted source '%s': %s", e.Source, strings.Join(e.Commands, "; "))
+}
+
+// TrustSources marks prompt files, or directories containing them, as
+// allowed to run command variables.
+func (r *Renderer) TrustSources(sources ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range sources {
+		if abs, err := filepath.Abs(s); err == nil {
+			s = abs
+		}
+		r.trusted = append(r.trusted, filepath.Clean(s))
+	}
+}
+
+// ApproveCommands allows the exact commands of p, in their directories, to
+// run for the rest of the session, regardless of where the prompt comes from.
+func (r *Renderer) ApproveCommands(p prompt.Prompt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range Commands(p) {
+		r.approved[approvalKey(p.Source, v)] = true
+	}
+}
+
+// NeedsApproval reports whether rendering p would run commands that are
+// neither trusted by source nor approved.
+func (r *Renderer) NeedsApproval(p prompt.Prompt) bool {
+	return r.untrusted(p) != nil
+}
+
+func (r *Renderer) untrusted(p prompt.Prompt) *UntrustedError {
+	commands := Commands(p)
+	if len(commands) == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.trusted {
+		if p.Source == t || strings.HasPrefix(p.Source, t+string(filepath.Separator)) {
+			return nil
+		}
+	}
+
+	var pending []string
+	for _, v := range commands {
+		if !r.approved[approvalKey(p.Source, v)] {
+			command := v.Command
+			if v.Dir != "" {
+				command += " (in " + v.Dir + ")"
+			}
+			pending = append(pending, command)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	return &UntrustedError{Source: p.Source, Commands: pending}
+}
+
+// Commands returns the command variables of p with their working directories
+// resolved as docs are: ~ expanded and relative to the prompts file, so a
+// prompt runs in the same place wherever promptgen is started.
+func Commands(p prompt.Prompt) []prompt.Variable {
+	commands := p.Commands()
+	for i, v := range commands {
+		if v.Dir != "" {
+			commands[i].Dir = docs.Resolve(v.Dir, p.Source)
+		}
+	}
+	return commands
+}
+
+// approvalKey identifies an approved command: the same command run from
+// another directory needs its own approval.
+func approvalKey(source string, v prompt.Variable) string {
+	return source + "\x00" + v.Dir + "\x00" + v.Command
+}
@@ -0,0 +1,118 @@
+package render
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/renatogalera/promptgen/internal/domain/markdown"
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+	"github.com/renatogalera/promptgen/internal/domain/resolver"
+	"github.com/renatogalera/promptgen/internal/domain/xml"
+)
+
+func newTestRenderer() *Renderer {
+	return New(prompt.NewService(), resolver.New(), xml.NewFormatter(), markdown.NewFormatter())
+}
+
+// commandPrompt writes a marker file in the directory of its prompts file
+// when its command runs.
+func commandPrompt(dir string) prompt.Prompt {
+	return prompt.Prompt{
+		Title:     "Marker",
+		Content:   "{{{out}}}",
+		Source:    filepath.Join(dir, "prompts.yaml"),
+		Variables: []prompt.Variable{{Name: "out", Type: "command", Command: "echo ran > marker", Dir: "."}},
+	}
+}
+
+func ran(t *testing.T, dir string) bool {
+	t.Helper()
+	_, err := os.Stat(filepath.Join(dir, "marker"))
+	return err == nil
+}
+
+func TestUntrustedCommandsNeedApproval(t *testing.T) {
+	dir := t.TempDir()
+	r := newTestRenderer()
+	p := commandPrompt(dir)
+
+	if !r.NeedsApproval(p) {
+		t.Fatal("NeedsApproval() = false for an untrusted source")
+	}
+	_, _, err := r.Prepare(p, nil)
+	var untrusted *UntrustedError
+	if !errors.As(err, &untrusted) {
+		t.Fatalf("Prepare() error = %v, want an *UntrustedError", err)
+	}
+	if ran(t, dir) {
+		t.Fatal("the command ran without approval")
+	}
+
+	r.ApproveCommands(p)
+	if _, _, err := r.Prepare(p, nil); err != nil {
+		t.Fatalf("Prepare() after approval error: %v", err)
+	}
+	if !ran(t, dir) {
+		t.Error("the approved command did not run")
+	}
+}
+
+func TestTrustedSourceRunsCommands(t *testing.T) {
+	dir := t.TempDir()
+	r := newTestRenderer()
+	r.TrustSources(dir)
+	p := commandPrompt(dir)
+
+	if r.NeedsApproval(p) {
+		t.Fatal("NeedsApproval() = true for a trusted source")
+	}
+	if _, _, err := r.Prepare(p, nil); err != nil {
+		t.Fatalf("Prepare() error: %v", err)
+	}
+	if !ran(t, dir) {
+		t.Error("the command of a trusted source did not run")
+	}
+}
+
+func TestApprovalCoversTheDirectory(t *testing.T) {
+	r := newTestRenderer()
+	p := commandPrompt(t.TempDir())
+	r.ApproveCommands(p)
+
+	p.Variables[0].Dir = "other"
+	if !r.NeedsApproval(p) {
+		t.Error("approving a command in one directory approved it in another")
+	}
+}
+
+func TestCommandDirIsRelativeToThePromptsFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses pwd")
+	}
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	r := newTestRenderer()
+	r.TrustSources(dir)
+	p := commandPrompt(dir)
+	p.Variables[0].Command, p.Variables[0].Dir = "pwd", "sub"
+
+	if got := Commands(p)[0].Dir; got != filepath.Join(dir, "sub") {
+		t.Errorf("Commands() dir = %q, want %q", got, filepath.Join(dir, "sub"))
+	}
+	prepared, _, err := r.Prepare(p, nil)
+	if err != nil {
+		t.Fatalf("Prepare() error: %v", err)
+	}
+	if got := strings.TrimSpace(prepared.Content); got != filepath.Join(dir, "sub") {
+		t.Errorf("command ran in %q, want %q", got, filepath.Join(dir, "sub"))
+	}
+}
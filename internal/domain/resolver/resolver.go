@@ -0,0 +1,96 @@
+package resolver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	Prefix    = "@"
+	envPrefix = "@env."
+)
+
+var builtinPattern = regexp.MustCompile(`\{\{(?:\{\s*|#(?:if|unless|each)\s+)(@[A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// Resolver computes the values of the reserved @-placeholders at render time.
+type Resolver struct {
+	now func() time.Time
+}
+
+func New() *Resolver {
+	return &Resolver{now: time.Now}
+}
+
+func IsBuiltin(name string) bool {
+	return strings.HasPrefix(name, Prefix)
+}
+
+// Names returns the distinct built-in variables referenced by content.
+func (r *Resolver) Names(content string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, match := range builtinPattern.FindAllStringSubmatch(content, -1) {
+		name := match[1]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Values resolves every built-in variable referenced by content.
+func (r *Resolver) Values(content string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, name := range r.Names(content) {
+		v, err := r.Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = v
+	}
+	return values, nil
+}
+
+func (r *Resolver) Resolve(name string) (string, error) {
+	if strings.HasPrefix(name, envPrefix) {
+		return os.Getenv(strings.TrimPrefix(name, envPrefix)), nil
+	}
+
+	switch name {
+	case "@date":
+		return r.now().Format("2006-01-02"), nil
+	case "@time":
+		return r.now().Format("15:04"), nil
+	case "@cwd":
+		return os.Getwd()
+	case "@user":
+		if u, err := user.Current(); err == nil {
+			return u.Username, nil
+		}
+		return os.Getenv("USER"), nil
+	case "@git.branch":
+		return r.git("rev-parse", "--abbrev-ref", "HEAD"), nil
+	case "@git.diff":
+		return r.git("diff"), nil
+	}
+
+	return "", fmt.Errorf("unknown built-in variable '%s'", name)
+}
+
+// git runs a git command and returns its trimmed output, or an empty string
+// when git is unavailable or the directory is not a repository.
+func (r *Resolver) git(args ...string) string {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(out), "\n")
+}
@@ -0,0 +1,74 @@
+package resolver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fixedResolver() *Resolver {
+	at := time.Date(2024, 2, 29, 7, 5, 0, 0, time.UTC)
+	return &Resolver{now: func() time.Time { return at }}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("PROMPTGEN_TEST_TEAM", "platform")
+	t.Setenv("PROMPTGEN_TEST_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "@date", want: "2024-02-29"},
+		{name: "@time", want: "07:05"},
+		{name: "@env.PROMPTGEN_TEST_TEAM", want: "platform"},
+		{name: "@env.PROMPTGEN_TEST_EMPTY", want: ""},
+		{name: "@env.PROMPTGEN_TEST_UNSET", want: ""},
+		{name: "@today", wantErr: true},
+		{name: "@git", wantErr: true},
+	}
+
+	r := fixedResolver()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Resolve(tt.name)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.name) {
+					t.Errorf("Resolve(%s) = %q, %v, want an error naming it", tt.name, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Resolve(%s) = %q, %v, want %q", tt.name, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	content := "{{{@time}}} {{{ @date }}} {{#if @env.CI}}ci{{/if}} {{{@date}}} {{{name}}} {{@user}} @cwd"
+	want := []string{"@date", "@env.CI", "@time"}
+	if got := fixedResolver().Names(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %q, want %q", got, want)
+	}
+}
+
+func TestValues(t *testing.T) {
+	t.Setenv("PROMPTGEN_TEST_TEAM", "platform")
+	r := fixedResolver()
+
+	got, err := r.Values("On {{{@date}}} at {{{@time}}} for {{{@env.PROMPTGEN_TEST_TEAM}}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"@date": "2024-02-29", "@time": "07:05", "@env.PROMPTGEN_TEST_TEAM": "platform"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+
+	if _, err := r.Values("{{{@date}}} {{{@nope}}}"); err == nil {
+		t.Error("Values() with an unknown built-in succeeded")
+	}
+}
@@ -0,0 +1,228 @@
+// Package search keeps an in-memory inverted index over the full text of the
+// prompts: title, tags, description, content and doc.
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+type Field int
+
+const (
+	FieldTitle Field = iota
+	FieldTags
+	FieldDescription
+	FieldContent
+	FieldDoc
+)
+
+var fieldNames = [...]string{"title", "tags", "description", "content", "doc"}
+
+// fieldWeights rank a term found in the title above one found in the tags,
+// and so on down to the doc.
+var fieldWeights = [...]int{8, 6, 3, 2, 1}
+
+func (f Field) String() string {
+	return fieldNames[f]
+}
+
+// snippetFields are searched, in order, for the line shown under a result.
+var snippetFields = []Field{FieldContent, FieldDescription, FieldDoc}
+
+const snippetWidth = 100
+
+type line struct {
+	field  Field
+	number int
+	text   string
+}
+
+// Hit is a prompt matching every term of a query, by its position in the
+// indexed slice.
+type Hit struct {
+	Doc   int
+	Score int
+}
+
+// Snippet is the line of a prompt that best matches a query.
+type Snippet struct {
+	Field Field
+	Line  int
+	Text  string
+}
+
+// Index maps each word to the prompts containing it. It is immutable once
+// built; the application builds a new one whenever the library is loaded,
+// which includes after every save.
+type Index struct {
+	lines    [][]line
+	postings map[string]map[int]int
+	terms    []string
+}
+
+// Build indexes the prompts in order. docText returns the text of a prompt's
+// doc, or an empty string when it has none or it cannot be read.
+func Build(prompts []prompt.Prompt, docText func(prompt.Prompt) string) *Index {
+	ix := &Index{
+		lines:    make([][]line, len(prompts)),
+		postings: make(map[string]map[int]int),
+	}
+
+	for doc, p := range prompts {
+		ix.add(doc, FieldTitle, p.Title)
+		ix.add(doc, FieldTags, strings.Join(p.Tags, " "))
+		ix.add(doc, FieldDescription, p.Description)
+		ix.add(doc, FieldContent, p.Content)
+		if p.HasDocs() && docText != nil {
+			ix.add(doc, FieldDoc, docText(p))
+		}
+	}
+
+	ix.terms = make([]string, 0, len(ix.postings))
+	for term := range ix.postings {
+		ix.terms = append(ix.terms, term)
+	}
+	sort.Strings(ix.terms)
+	return ix
+}
+
+func (ix *Index) add(doc int, field Field, text string) {
+	for n, t := range strings.Split(text, "\n") {
+		if strings.TrimSpace(t) == "" {
+			continue
+		}
+		ix.lines[doc] = append(ix.lines[doc], line{field: field, number: n + 1, text: t})
+		for _, term := range Tokenize(t) {
+			docs := ix.postings[term]
+			if docs == nil {
+				docs = make(map[int]int)
+				ix.postings[term] = docs
+			}
+			docs[doc] += fieldWeights[field]
+		}
+	}
+}
+
+// Search returns the prompts containing every word of the query, best first.
+// Query words match indexed words by prefix, so results narrow while typing.
+func (ix *Index) Search(query string) []Hit {
+	words := Tokenize(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var scores map[int]int
+	for _, word := range words {
+		matched := ix.lookup(word)
+		if scores == nil {
+			scores = matched
+			continue
+		}
+		for doc, score := range scores {
+			if extra, ok := matched[doc]; ok {
+				scores[doc] = score + extra
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for doc, score := range scores {
+		hits = append(hits, Hit{Doc: doc, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Doc < hits[j].Doc
+	})
+	return hits
+}
+
+func (ix *Index) lookup(word string) map[int]int {
+	matched := make(map[int]int)
+	for i := sort.SearchStrings(ix.terms, word); i < len(ix.terms) && strings.HasPrefix(ix.terms[i], word); i++ {
+		for doc, score := range ix.postings[ix.terms[i]] {
+			if ix.terms[i] == word {
+				score *= 2
+			}
+			matched[doc] += score
+		}
+	}
+	return matched
+}
+
+// Snippet returns the content, description or doc line of a prompt containing
+// the most words of the query, shortened around the first match.
+func (ix *Index) Snippet(doc int, query string) (Snippet, bool) {
+	if doc < 0 || doc >= len(ix.lines) {
+		return Snippet{}, false
+	}
+	words := Tokenize(query)
+
+	var best line
+	bestCount := 0
+	for _, field := range snippetFields {
+		for _, l := range ix.lines[doc] {
+			if l.field != field {
+				continue
+			}
+			lower := strings.ToLower(l.text)
+			count := 0
+			for _, word := range words {
+				if strings.Contains(lower, word) {
+					count++
+				}
+			}
+			if count > bestCount {
+				best, bestCount = l, count
+			}
+		}
+	}
+	if bestCount == 0 {
+		return Snippet{}, false
+	}
+
+	return Snippet{Field: best.field, Line: best.number, Text: excerpt(best.text, words)}, true
+}
+
+// Tokenize splits text into lowercase words of letters and digits.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func excerpt(text string, words []string) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= snippetWidth {
+		return string(runes)
+	}
+
+	lower := []rune(strings.ToLower(string(runes)))
+	first := len(lower)
+	for _, word := range words {
+		if i := strings.Index(string(lower), word); i >= 0 {
+			first = min(first, len([]rune(string(lower)[:i])))
+		}
+	}
+	if first == len(lower) {
+		first = 0
+	}
+
+	start := max(0, min(first-snippetWidth/4, len(runes)-snippetWidth))
+	end := min(len(runes), start+snippetWidth)
+	out := string(runes[start:end])
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
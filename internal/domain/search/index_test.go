@@ -0,0 +1,162 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Hello, World! go1.22 naïve_code")
+	want := []string{"hello", "world", "go1", "22", "naïve", "code"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() = %q, want %q", got, want)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	prompts := []prompt.Prompt{
+		{Title: "Go Review", Tags: []string{"go"}, Content: "Check the goroutines"},
+		{Title: "Rust", Description: "Make it go fast", Content: "Check the borrows"},
+		{Title: "Notes", Doc: "notes.md", Content: "Nothing"},
+	}
+	docText := func(p prompt.Prompt) string { return "Deployment runbook" }
+	ix := Build(prompts, docText)
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"go", []int{0, 1}},
+		{"rev", []int{0}},
+		{"GO review", []int{0}},
+		{"check", []int{0, 1}},
+		{"runbook", []int{2}},
+		{"go borrows", []int{1}},
+		{"missing", []int{}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			hits := ix.Search(tt.query)
+			var got []int
+			if hits != nil {
+				got = []int{}
+			}
+			for _, hit := range hits {
+				got = append(got, hit.Doc)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchScoresFieldsAndExactWords(t *testing.T) {
+	prompts := []prompt.Prompt{
+		{Title: "Other", Content: "review"},
+		{Title: "Review"},
+		{Title: "Reviewer"},
+	}
+	hits := Build(prompts, nil).Search("review")
+	var got []int
+	for _, hit := range hits {
+		got = append(got, hit.Doc)
+	}
+	// An exact title word beats a prefix of one, which beats the content.
+	if want := []int{1, 2, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Search() order = %v, want %v (%v)", got, want, hits)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("a", 150) + " needle " + strings.Repeat("b", 42)
+	prompts := []prompt.Prompt{
+		{
+			Title:       "Deploy",
+			Description: "Ship the service",
+			Content:     "First line\nRun the deploy script\nThen deploy and verify the service",
+		},
+		{Title: "Long", Content: long},
+	}
+	ix := Build(prompts, nil)
+
+	tests := []struct {
+		name  string
+		doc   int
+		query string
+		want  Snippet
+		found bool
+	}{
+		{
+			name:  "line with the most words",
+			doc:   0,
+			query: "deploy service",
+			want:  Snippet{Field: FieldContent, Line: 3, Text: "Then deploy and verify the service"},
+			found: true,
+		},
+		{
+			name:  "description when the content has no match",
+			doc:   0,
+			query: "ship",
+			want:  Snippet{Field: FieldDescription, Line: 1, Text: "Ship the service"},
+			found: true,
+		},
+		{
+			name:  "ties go to the first line",
+			doc:   0,
+			query: "deploy",
+			want:  Snippet{Field: FieldContent, Line: 2, Text: "Run the deploy script"},
+			found: true,
+		},
+		{name: "no match", doc: 0, query: "rollback"},
+		{name: "out of range", doc: 5, query: "deploy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := ix.Snippet(tt.doc, tt.query)
+			if found != tt.found || got != tt.want {
+				t.Errorf("Snippet(%d, %q) = %+v, %v, want %+v, %v", tt.doc, tt.query, got, found, tt.want, tt.found)
+			}
+		})
+	}
+
+	got, ok := ix.Snippet(1, "needle")
+	if !ok || !strings.HasPrefix(got.Text, "…") || !strings.Contains(got.Text, "needle") {
+		t.Fatalf("Snippet() of a long line = %q, want an excerpt around the match", got.Text)
+	}
+	if n := utf8.RuneCountInString(got.Text); n != snippetWidth+1 {
+		t.Errorf("excerpt is %d runes, want %d and an ellipsis", n, snippetWidth)
+	}
+}
+
+// Prompts sharing a title are told apart by their position in the library,
+// not by their title or key.
+func TestIndexKeysByPosition(t *testing.T) {
+	prompts := []prompt.Prompt{
+		{Title: "Same", Content: "alpha zebra"},
+		{Title: "Same", Content: "beta zebra"},
+	}
+	ix := Build(prompts, nil)
+
+	if hits := ix.Search("beta"); len(hits) != 1 || hits[0].Doc != 1 {
+		t.Errorf("Search(beta) = %v, want only doc 1", hits)
+	}
+	if hits := ix.Search("zebra"); len(hits) != 2 || hits[0].Doc != 0 || hits[1].Doc != 1 {
+		t.Errorf("Search(zebra) = %v, want docs 0 and 1", hits)
+	}
+	for doc, want := range []string{"alpha zebra", "beta zebra"} {
+		if got, ok := ix.Snippet(doc, "zebra"); !ok || got.Text != want {
+			t.Errorf("Snippet(%d) = %q, want %q", doc, got.Text, want)
+		}
+	}
+	if _, ok := ix.Snippet(0, "beta"); ok {
+		t.Errorf("Snippet(0, beta) found a line of the other prompt")
+	}
+}
@@ -0,0 +1,173 @@
+// Package secrets finds credentials in rendered prompts, such as API keys
+// pasted into a variable or sitting in an included doc, so they can be
+// redacted before the prompt leaves the machine.
+package secrets
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Rule is a named pattern. When the pattern has a capture group, only the
+// first group is the secret, so `token: (\S+)` redacts the value but keeps
+// the key.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Match is a secret found in a text, by byte offsets.
+type Match struct {
+	Rule  string
+	Start int
+	End   int
+}
+
+// EntropyRule is the name of matches found by their randomness rather than
+// by a pattern.
+const EntropyRule = "high-entropy string"
+
+// BuiltinRules are always checked.
+var BuiltinRules = []Rule{
+	{Name: "AWS access key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`)},
+	{Name: "AWS secret key", Pattern: regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`)},
+	{Name: "GitHub token", Pattern: regexp.MustCompile(`(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`)},
+	{Name: "private key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY( BLOCK)?-----[\s\S]*?(?:-----END [A-Z ]*PRIVATE KEY( BLOCK)?-----|\z)`)},
+	{Name: "JWT", Pattern: regexp.MustCompile(`eyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{10,}`)},
+}
+
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_=-]{24,}`)
+
+// minEntropy is the Shannon entropy, in bits per character, above which a
+// candidate counts as random. Hex digests stay below it. Short strings
+// cannot reach it, so they are held to entropyMargin below their maximum.
+const (
+	minEntropy    = 4.2
+	entropyMargin = 0.6
+)
+
+// minSwitchRatio is the share of adjacent characters that must change
+// between upper case, lower case and digits. Random keys switch at about
+// two in three; identifiers made of words, like CamelCase names, far less.
+const minSwitchRatio = 0.4
+
+// Scanner checks texts against the built-in rules, extra rules and, unless
+// disabled, the entropy heuristic.
+type Scanner struct {
+	rules   []Rule
+	entropy bool
+}
+
+// Pattern is a user-defined rule as written in the config file.
+type Pattern struct {
+	Name  string
+	Regex string
+}
+
+// NewScanner compiles the extra patterns after the built-in rules.
+func NewScanner(patterns []Pattern, entropy bool) (*Scanner, error) {
+	s := &Scanner{rules: append([]Rule(nil), BuiltinRules...), entropy: entropy}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret pattern '%s': %w", p.Name, err)
+		}
+		name := p.Name
+		if name == "" {
+			name = p.Regex
+		}
+		s.rules = append(s.rules, Rule{Name: name, Pattern: re})
+	}
+	return s, nil
+}
+
+// Scan returns the secrets in text in order. Overlapping matches are merged
+// into the first one.
+func (s *Scanner) Scan(text string) []Match {
+	var found []Match
+	for _, rule := range s.rules {
+		for _, loc := range rule.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			if start < end {
+				found = append(found, Match{Rule: rule.Name, Start: start, End: end})
+			}
+		}
+	}
+	if s.entropy {
+		for _, loc := range entropyCandidate.FindAllStringIndex(text, -1) {
+			if random(text[loc[0]:loc[1]]) {
+				found = append(found, Match{Rule: EntropyRule, Start: loc[0], End: loc[1]})
+			}
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Start != found[j].Start {
+			return found[i].Start < found[j].Start
+		}
+		return found[i].End > found[j].End
+	})
+	var matches []Match
+	for _, m := range found {
+		if n := len(matches); n > 0 && m.Start < matches[n-1].End {
+			matches[n-1].End = max(matches[n-1].End, m.End)
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// Redact replaces every match with a [REDACTED: rule] marker.
+func Redact(text string, matches []Match) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.Start])
+		b.WriteString("[REDACTED: " + m.Rule + "]")
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// random reports whether s mixes upper case, lower case and digits with a
+// high entropy, as generated keys do.
+func random(s string) bool {
+	var upper, lower, digit bool
+	counts := make(map[rune]int)
+	switches, prev := 0, -1
+	for _, r := range s {
+		class := 0
+		switch {
+		case unicode.IsUpper(r):
+			upper, class = true, 1
+		case unicode.IsLower(r):
+			lower, class = true, 2
+		case unicode.IsDigit(r):
+			digit, class = true, 3
+		}
+		if prev >= 0 && class != prev {
+			switches++
+		}
+		prev = class
+		counts[r]++
+	}
+	if !upper || !lower || !digit || float64(switches) < minSwitchRatio*float64(len(s)-1) {
+		return false
+	}
+
+	entropy := 0.0
+	for _, n := range counts {
+		p := float64(n) / float64(len(s))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy >= min(minEntropy, math.Log2(float64(len(s)))-entropyMargin)
+}
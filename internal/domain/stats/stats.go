@@ -0,0 +1,189 @@
+// Package stats summarises the local usage events of a prompt library.
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+const (
+	EventRendered = "rendered"
+	EventCopied   = "copied"
+)
+
+// Event records one use of a prompt. Only metadata is kept: never variable
+// values or rendered output.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Prompt    string    `json:"prompt"`
+	Format    string    `json:"format"`
+	Variables int       `json:"variables"`
+}
+
+// Options select how much the report covers.
+type Options struct {
+	Top        int
+	UnusedDays int
+	Weeks      int
+}
+
+var DefaultOptions = Options{Top: 10, UnusedDays: 30, Weeks: 8}
+
+type PromptCount struct {
+	Prompt string `json:"prompt"`
+	Title  string `json:"title"`
+	Count  int    `json:"count"`
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type WeekCount struct {
+	Week  string `json:"week"`
+	Count int    `json:"count"`
+}
+
+type Report struct {
+	Events     int            `json:"events"`
+	Since      *time.Time     `json:"since,omitempty"`
+	Top        []PromptCount  `json:"top_prompts"`
+	Unused     []string       `json:"unused_prompts"`
+	UnusedDays int            `json:"unused_days"`
+	Tags       []TagCount     `json:"tags"`
+	Weekly     []WeekCount    `json:"weekly"`
+	Formats    map[string]int `json:"formats"`
+}
+
+// Compute builds a report of the events against the prompts currently in the
+// library, as of now. Events are bucketed into the weeks of now's time zone.
+func Compute(events []Event, prompts []prompt.Prompt, now time.Time, opts Options) Report {
+	report := Report{
+		Events:     len(events),
+		UnusedDays: opts.UnusedDays,
+		Formats:    make(map[string]int),
+	}
+
+	byKey := make(map[string]prompt.Prompt, len(prompts))
+	for _, p := range prompts {
+		byKey[p.Key()] = p
+	}
+
+	counts := make(map[string]int)
+	lastUsed := make(map[string]time.Time)
+	tags := make(map[string]int)
+	weeks := make(map[string]int)
+	for _, e := range events {
+		if report.Since == nil || e.Time.Before(*report.Since) {
+			since := e.Time
+			report.Since = &since
+		}
+		counts[e.Prompt]++
+		if e.Time.After(lastUsed[e.Prompt]) {
+			lastUsed[e.Prompt] = e.Time
+		}
+		if e.Format != "" {
+			report.Formats[e.Format]++
+		}
+		weeks[weekStart(e.Time.In(now.Location())).Format(time.DateOnly)]++
+		for _, tag := range byKey[e.Prompt].Tags {
+			tags[tag]++
+		}
+	}
+
+	for key, count := range counts {
+		title := key
+		if p, ok := byKey[key]; ok {
+			title = p.Title
+		}
+		report.Top = append(report.Top, PromptCount{Prompt: key, Title: title, Count: count})
+	}
+	sort.Slice(report.Top, func(i, j int) bool {
+		if report.Top[i].Count != report.Top[j].Count {
+			return report.Top[i].Count > report.Top[j].Count
+		}
+		return report.Top[i].Title < report.Top[j].Title
+	})
+	if opts.Top > 0 && len(report.Top) > opts.Top {
+		report.Top = report.Top[:opts.Top]
+	}
+
+	cutoff := now.AddDate(0, 0, -opts.UnusedDays)
+	report.Unused = []string{}
+	for _, p := range prompts {
+		if lastUsed[p.Key()].Before(cutoff) {
+			report.Unused = append(report.Unused, p.Title)
+		}
+	}
+
+	for tag, count := range tags {
+		report.Tags = append(report.Tags, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(report.Tags, func(i, j int) bool {
+		if report.Tags[i].Count != report.Tags[j].Count {
+			return report.Tags[i].Count > report.Tags[j].Count
+		}
+		return report.Tags[i].Tag < report.Tags[j].Tag
+	})
+
+	start := weekStart(now).AddDate(0, 0, -7*(max(opts.Weeks, 1)-1))
+	for week := start; !week.After(now); week = week.AddDate(0, 0, 7) {
+		key := week.Format(time.DateOnly)
+		report.Weekly = append(report.Weekly, WeekCount{Week: key, Count: weeks[key]})
+	}
+
+	return report
+}
+
+// weekStart returns the Monday starting the week of t.
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// WriteText prints the report for people.
+func (r Report) WriteText(w io.Writer) {
+	if r.Events == 0 {
+		fmt.Fprintln(w, "No usage recorded yet.")
+		return
+	}
+	fmt.Fprintf(w, "%d uses since %s\n", r.Events, r.Since.Local().Format(time.DateOnly))
+
+	fmt.Fprintln(w, "\nTop prompts")
+	for i, pc := range r.Top {
+		fmt.Fprintf(w, "  %2d. %-40s %5d\n", i+1, pc.Title, pc.Count)
+	}
+
+	fmt.Fprintf(w, "\nUnused in the last %d days (%d)\n", r.UnusedDays, len(r.Unused))
+	for _, title := range r.Unused {
+		fmt.Fprintf(w, "  %s\n", title)
+	}
+
+	if len(r.Tags) > 0 {
+		fmt.Fprintln(w, "\nTag usage")
+		for _, tc := range r.Tags {
+			fmt.Fprintf(w, "  %-30s %5d\n", tc.Tag, tc.Count)
+		}
+	}
+
+	fmt.Fprintln(w, "\nWeekly uses")
+	peak := 0
+	for _, wc := range r.Weekly {
+		peak = max(peak, wc.Count)
+	}
+	for _, wc := range r.Weekly {
+		bar := ""
+		if peak > 0 {
+			bar = strings.Repeat("█", (wc.Count*30+peak-1)/peak)
+		}
+		fmt.Fprintln(w, strings.TrimRight(fmt.Sprintf("  %s %5d %s", wc.Week, wc.Count, bar), " "))
+	}
+}
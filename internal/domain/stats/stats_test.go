@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+func utc(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestComputeTopAndTags(t *testing.T) {
+	prompts := []prompt.Prompt{
+		{ID: "a", Title: "Alpha", Tags: []string{"go", "review"}},
+		{ID: "b", Title: "Beta", Tags: []string{"go"}},
+		{ID: "c", Title: "Gamma"},
+	}
+	now := utc(2024, 6, 30, 12, 0)
+	events := []Event{
+		{Time: utc(2024, 6, 28, 9, 0), Type: EventCopied, Prompt: "a", Format: "xml"},
+		{Time: utc(2024, 6, 27, 9, 0), Type: EventRendered, Prompt: "a", Format: "markdown"},
+		{Time: utc(2024, 6, 26, 9, 0), Type: EventCopied, Prompt: "b", Format: "xml"},
+		{Time: utc(2024, 6, 25, 9, 0), Type: EventCopied, Prompt: "gone"},
+	}
+
+	report := Compute(events, prompts, now, Options{Top: 2, UnusedDays: 30, Weeks: 1})
+
+	if report.Events != 4 {
+		t.Errorf("Events = %d, want 4", report.Events)
+	}
+	if want := utc(2024, 6, 25, 9, 0); report.Since == nil || !report.Since.Equal(want) {
+		t.Errorf("Since = %v, want %v", report.Since, want)
+	}
+	wantTop := []PromptCount{{Prompt: "a", Title: "Alpha", Count: 2}, {Prompt: "b", Title: "Beta", Count: 1}}
+	if !reflect.DeepEqual(report.Top, wantTop) {
+		t.Errorf("Top = %+v, want %+v", report.Top, wantTop)
+	}
+	wantTags := []TagCount{{Tag: "go", Count: 3}, {Tag: "review", Count: 2}}
+	if !reflect.DeepEqual(report.Tags, wantTags) {
+		t.Errorf("Tags = %+v, want %+v", report.Tags, wantTags)
+	}
+	wantFormats := map[string]int{"xml": 2, "markdown": 1}
+	if !reflect.DeepEqual(report.Formats, wantFormats) {
+		t.Errorf("Formats = %v, want %v", report.Formats, wantFormats)
+	}
+}
+
+func TestComputeUnused(t *testing.T) {
+	now := utc(2024, 6, 30, 12, 0)
+	cutoff := now.AddDate(0, 0, -30)
+	prompts := []prompt.Prompt{
+		{Title: "fresh"},
+		{Title: "stale"},
+		{Title: "never"},
+		{Title: "at the cutoff"},
+		{Title: "just before"},
+	}
+	events := []Event{
+		{Time: utc(2024, 6, 20, 0, 0), Prompt: "fresh"},
+		{Time: utc(2024, 5, 1, 0, 0), Prompt: "stale"},
+		{Time: cutoff, Prompt: "at the cutoff"},
+		{Time: cutoff.Add(-time.Second), Prompt: "just before"},
+		{Time: utc(2024, 6, 29, 0, 0), Prompt: "deleted"},
+	}
+
+	report := Compute(events, prompts, now, Options{UnusedDays: 30, Weeks: 1})
+
+	want := []string{"stale", "never", "just before"}
+	if !reflect.DeepEqual(report.Unused, want) {
+		t.Errorf("Unused = %q, want %q", report.Unused, want)
+	}
+
+	if empty := Compute(nil, nil, now, DefaultOptions); empty.Unused == nil || empty.Since != nil {
+		t.Errorf("Compute() without events = %+v, want an empty Unused list and no Since", empty)
+	}
+}
+
+func TestComputeWeekly(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		weeks  int
+		events []time.Time
+		want   []WeekCount
+	}{
+		{
+			name:  "across the new year",
+			now:   utc(2025, 1, 8, 12, 0),
+			weeks: 3,
+			events: []time.Time{
+				utc(2024, 12, 1, 12, 0), // before the window
+				utc(2024, 12, 29, 23, 59),
+				utc(2024, 12, 31, 10, 0),
+				utc(2025, 1, 1, 0, 0),
+				utc(2025, 1, 6, 0, 0),
+			},
+			want: []WeekCount{{Week: "2024-12-23", Count: 1}, {Week: "2024-12-30", Count: 2}, {Week: "2025-01-06", Count: 1}},
+		},
+		{
+			name:  "across a DST change, in now's time zone",
+			now:   time.Date(2024, 4, 3, 12, 0, 0, 0, berlin),
+			weeks: 2,
+			events: []time.Time{
+				utc(2024, 3, 31, 21, 30), // Sunday 23:30 in Berlin
+				utc(2024, 3, 31, 22, 30), // Monday 00:30 in Berlin
+			},
+			want: []WeekCount{{Week: "2024-03-25", Count: 1}, {Week: "2024-04-01", Count: 1}},
+		},
+		{
+			name:  "at least one week",
+			now:   utc(2024, 6, 30, 12, 0),
+			weeks: 0,
+			want:  []WeekCount{{Week: "2024-06-24", Count: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []Event
+			for _, at := range tt.events {
+				events = append(events, Event{Time: at, Prompt: "p"})
+			}
+			report := Compute(events, nil, tt.now, Options{Weeks: tt.weeks})
+			if !reflect.DeepEqual(report.Weekly, tt.want) {
+				t.Errorf("Weekly = %+v, want %+v", report.Weekly, tt.want)
+			}
+		})
+	}
+}
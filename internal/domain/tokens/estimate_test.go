@@ -0,0 +1,91 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		// want holds the tokens expected for gpt, claude, llama and gemini.
+		want  [4]int
+		chars int
+	}{
+		{name: "empty", text: "", want: [4]int{0, 0, 0, 0}, chars: 0},
+		{name: "short words", text: "Hello world", want: [4]int{2, 2, 2, 2}, chars: 11},
+		{name: "long word", text: "internationalization", want: [4]int{5, 6, 6, 5}, chars: 20},
+		{name: "word at the family's limit", text: "reviews", want: [4]int{1, 2, 1, 1}, chars: 7},
+		{name: "numbers in threes", text: "12345", want: [4]int{2, 2, 2, 2}, chars: 5},
+		{name: "contraction", text: "don't", want: [4]int{2, 2, 2, 2}, chars: 5},
+		{name: "punctuation run", text: "```", want: [4]int{2, 2, 2, 2}, chars: 3},
+		{name: "whitespace", text: "a\n\n\n\n\n\n\n\n\nb", want: [4]int{4, 4, 4, 4}, chars: 11},
+		{name: "CJK", text: "日本語のテキスト", want: [4]int{7, 8, 8, 6}, chars: 8},
+		{name: "mixed script word", text: "Go言語", want: [4]int{3, 3, 3, 3}, chars: 4},
+		{name: "accented word", text: "café", want: [4]int{1, 1, 1, 1}, chars: 4},
+		{name: "code", text: "if (x) {\n\treturn y;\n}", want: [4]int{11, 11, 11, 11}, chars: 21},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, family := range Families {
+				got := Estimate(tt.text, family)
+				if got.Tokens != tt.want[i] || got.Chars != tt.chars {
+					t.Errorf("Estimate(%q, %s) = %+v, want %d tokens and %d chars", tt.text, family.Name, got, tt.want[i], tt.chars)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateCodeCostsMoreThanProse(t *testing.T) {
+	prose := strings.Repeat("Please review the following change and explain it. ", 20)
+	code := strings.Repeat("if err != nil { return fmt.Errorf(\"x: %w\", err) }\n", 20)
+	family := Families[0]
+
+	proseRatio := float64(Estimate(prose, family).Chars) / float64(Estimate(prose, family).Tokens)
+	codeRatio := float64(Estimate(code, family).Chars) / float64(Estimate(code, family).Tokens)
+	if codeRatio >= proseRatio {
+		t.Errorf("code has %.2f chars per token, prose %.2f; want code to be denser in tokens", codeRatio, proseRatio)
+	}
+	if proseRatio < 3 || proseRatio > 6 {
+		t.Errorf("prose has %.2f chars per token, want an English-like 3 to 6", proseRatio)
+	}
+}
+
+func TestParseFamily(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "", want: "gpt"},
+		{name: " Claude ", want: "claude"},
+		{name: "gemini", want: "gemini"},
+		{name: "bard", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFamily(tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseFamily(%q) = %s, want an error", tt.name, got.Name)
+			}
+			continue
+		}
+		if err != nil || got.Name != tt.want {
+			t.Errorf("ParseFamily(%q) = %s, %v, want %s", tt.name, got.Name, err, tt.want)
+		}
+	}
+}
+
+func TestFamilyNextWraps(t *testing.T) {
+	f := Families[0]
+	for range Families {
+		f = f.Next()
+	}
+	if f.Name != Families[0].Name {
+		t.Errorf("cycling through every family ended on %s, want %s", f.Name, Families[0].Name)
+	}
+}
@@ -3,7 +3,6 @@ package xml
 import (
 	"encoding/xml"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/renatogalera/promptgen/internal/domain/prompt"
@@ -17,12 +16,22 @@ func (c CDATA) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	}{Data: string(c)}, start)
 }
 
+type XMLFile struct {
+	Path    string `xml:"path,attr"`
+	Content CDATA  `xml:",cdata"`
+}
+
+type XMLFiles struct {
+	Files []XMLFile `xml:"file"`
+}
+
 type XMLPrompt struct {
-	XMLName     xml.Name `xml:"prompt"`
-	Title       string   `xml:"title"`
-	Tags        string   `xml:"tags,omitempty"`
-	Description string   `xml:"description,omitempty"`
-	Content     CDATA    `xml:"content"`
+	XMLName     xml.Name  `xml:"prompt"`
+	Title       string    `xml:"title"`
+	Tags        string    `xml:"tags,omitempty"`
+	Description string    `xml:"description,omitempty"`
+	Content     CDATA     `xml:"content"`
+	Files       *XMLFiles `xml:"files,omitempty"`
 }
 
 type Formatter struct{}
@@ -38,6 +47,12 @@ func (f *Formatter) FormatAsXML(p prompt.Prompt) (string, error) {
 		Description: p.Description,
 		Content:     CDATA(p.Content),
 	}
+	if len(p.Files) > 0 {
+		x.Files = &XMLFiles{}
+		for _, file := range p.Files {
+			x.Files.Files = append(x.Files.Files, XMLFile{Path: file.Path, Content: CDATA(file.Content)})
+		}
+	}
 
 	data, err := xml.MarshalIndent(x, "", "    ")
 	if err != nil {
@@ -47,22 +62,22 @@ func (f *Formatter) FormatAsXML(p prompt.Prompt) (string, error) {
 	return xml.Header + string(data), nil
 }
 
+// FormatWithDoc formats the prompt followed by each loaded doc in its own
+// <doc path="…"> element.
 func (f *Formatter) FormatWithDoc(p prompt.Prompt) (string, error) {
 	baseOutput, err := f.FormatAsXML(p)
 	if err != nil {
 		return "", err
 	}
 
-	if p.Doc == "" {
-		return baseOutput, nil
+	var b strings.Builder
+	b.WriteString(baseOutput)
+	for _, doc := range p.DocFiles {
+		var path strings.Builder
+		if err := xml.EscapeText(&path, []byte(doc.Path)); err != nil {
+			return "", fmt.Errorf("failed to escape doc path: %w", err)
+		}
+		b.WriteString("\n\n<doc path=\"" + path.String() + "\">\n" + doc.Content + "\n</doc>")
 	}
-
-	docContent, err := os.ReadFile(p.Doc)
-	if err != nil {
-		return baseOutput, fmt.Errorf("error loading doc file '%s': %w", p.Doc, err)
-
-	}
-
-	fullOutput := baseOutput + "\n\n<doc>\n" + string(docContent) + "\n</doc>"
-	return fullOutput, nil
+	return b.String(), nil
 }
@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/renatogalera/promptgen/internal/domain/stats"
+)
+
+// Log is an append-only file of events, one JSON object per line.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+func (l *Log) Append(event stats.Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0750); err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", filepath.Dir(l.path), err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open events file '%s': %w", l.path, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write events file '%s': %w", l.path, err)
+	}
+	return nil
+}
+
+// Load reads every event. A missing file yields no events; malformed lines
+// are skipped.
+func (l *Log) Load() ([]stats.Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(l.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read events file '%s': %w", l.path, err)
+	}
+	defer f.Close()
+
+	var events []stats.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event stats.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err == nil {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events file '%s': %w", l.path, err)
+	}
+	return events, nil
+}
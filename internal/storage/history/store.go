@@ -0,0 +1,117 @@
+// Package history keeps the prompts rendered from the interface, so an
+// earlier output can be copied again or re-opened with the same variables.
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Entry is one rendered prompt.
+type Entry struct {
+	ID        string            `json:"id"`
+	Time      time.Time         `json:"time"`
+	Prompt    string            `json:"prompt"`
+	Title     string            `json:"title"`
+	Format    string            `json:"format"`
+	Variables map[string]string `json:"variables,omitempty"`
+	Output    string            `json:"output"`
+}
+
+// Store is a JSON file of entries, newest first, rewritten on every change.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore returns a store backed by path. An empty path records nothing.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load returns the entries, newest first. A missing file yields none.
+func (s *Store) Load() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Add records an entry, keeping at most limit entries, and returns it with
+// its ID set.
+func (s *Store) Add(entry Entry, limit int) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return entry, err
+	}
+	entry.ID = strconv.FormatInt(entry.Time.UnixNano(), 36)
+	entries = append([]Entry{entry}, entries...)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entry, s.save(entries)
+}
+
+// Delete removes the entry with the given ID.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i, e := range entries {
+		if e.ID == id {
+			return s.save(append(entries[:i], entries[i+1:]...))
+		}
+	}
+	return fmt.Errorf("history entry '%s' not found", id)
+}
+
+func (s *Store) load() ([]Entry, error) {
+	if s.path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read history file '%s': %w", s.path, err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse history file '%s': %w", s.path, err)
+	}
+	return entries, nil
+}
+
+func (s *Store) save(entries []Entry) error {
+	if s.path == "" {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(entries); err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", filepath.Dir(s.path), err)
+	}
+	if err := os.WriteFile(s.path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write history file '%s': %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+// State is what promptgen remembers between runs on this machine. Unlike the
+// prompts file it is never meant to be shared.
+type State struct {
+	Variables map[string]map[string][]string `yaml:"variables,omitempty"`
+	Usage     map[string]prompt.Usage        `yaml:"usage,omitempty"`
+	SortMode  prompt.SortMode                `yaml:"sort_mode,omitempty"`
+}
+
+type Store struct {
+	path  string
+	mu    sync.Mutex
+	state State
+}
+
+// NewStore returns a store backed by path. An empty path keeps the state in
+// memory only.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+func (s *Store) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.path == "" {
+		return nil
+	}
+	state, err := s.read()
+	if err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+// VariableHistory returns the remembered values of a prompt variable, most
+// recent first.
+func (s *Store) VariableHistory(promptKey, name string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.state.Variables[promptKey][name]
+	return append([]string(nil), history...)
+}
+
+// RememberVariables records values as the most recent ones of their
+// variables, keeping at most limit values per variable, and saves the state.
+func (s *Store) RememberVariables(promptKey string, values map[string]string, limit int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.update(func(state *State) {
+		if state.Variables == nil {
+			state.Variables = make(map[string]map[string][]string)
+		}
+		vars := state.Variables[promptKey]
+		if vars == nil {
+			vars = make(map[string][]string)
+			state.Variables[promptKey] = vars
+		}
+
+		for name, value := range values {
+			if value == "" {
+				continue
+			}
+			history := []string{value}
+			for _, old := range vars[name] {
+				if old != value {
+					history = append(history, old)
+				}
+			}
+			if len(history) > limit {
+				history = history[:limit]
+			}
+			vars[name] = history
+		}
+	})
+}
+
+// Usage returns how often and when the prompt was last used.
+func (s *Store) Usage(promptKey string) prompt.Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.Usage[promptKey]
+}
+
+// RecordUse counts a use of the prompt at the given time and saves the state.
+func (s *Store) RecordUse(promptKey string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.update(func(state *State) {
+		if state.Usage == nil {
+			state.Usage = make(map[string]prompt.Usage)
+		}
+		usage := state.Usage[promptKey]
+		usage.Count++
+		usage.LastUsed = at
+		state.Usage[promptKey] = usage
+	})
+}
+
+// SortMode returns the list order chosen last, title by default.
+func (s *Store) SortMode() prompt.SortMode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mode, err := prompt.ParseSortMode(string(s.state.SortMode)); err == nil {
+		return mode
+	}
+	return prompt.SortTitle
+}
+
+func (s *Store) SetSortMode(mode prompt.SortMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.update(func(state *State) {
+		state.SortMode = mode
+	})
+}
+
+// update applies change to the state as saved on disk rather than to the one
+// loaded at start, so what another running promptgen saved in the meantime is
+// kept, and saves the result.
+func (s *Store) update(change func(state *State)) error {
+	if s.path == "" {
+		change(&s.state)
+		return nil
+	}
+
+	state, err := s.read()
+	if err != nil {
+		// A file that cannot be read or parsed is overwritten with the
+		// state in memory.
+		state = s.state
+	}
+	change(&state)
+	s.state = state
+	return s.write(state)
+}
+
+func (s *Store) read() (State, error) {
+	var state State
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return state, fmt.Errorf("failed to read state file '%s': %w", s.path, err)
+	}
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return State{}, fmt.Errorf("failed to parse state file '%s': %w", s.path, err)
+	}
+	return state, nil
+}
+
+// write saves the state to a temporary file renamed over the state file, so
+// a crash mid-write never leaves a truncated file behind.
+func (s *Store) write(state State) error {
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save state file '%s': %w", s.path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save state file '%s': %w", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save state file '%s': %w", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save state file '%s': %w", s.path, err)
+	}
+	return nil
+}
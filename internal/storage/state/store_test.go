@@ -0,0 +1,92 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/renatogalera/promptgen/internal/domain/prompt"
+)
+
+func TestConcurrentStoresKeepEachOthersChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.yaml")
+	at := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	first, second := NewStore(path), NewStore(path)
+	for _, s := range []*Store{first, second} {
+		if err := s.Load(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := first.RecordUse("review", at); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.RecordUse("review", at.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.SetSortMode(prompt.SortRecent); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.RememberVariables("review", map[string]string{"lang": "go"}, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewStore(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := loaded.Usage("review"), (prompt.Usage{Count: 2, LastUsed: at.Add(time.Hour)}); got != want {
+		t.Errorf("Usage() = %+v, want %+v", got, want)
+	}
+	if got := loaded.SortMode(); got != prompt.SortRecent {
+		t.Errorf("SortMode() = %s, want %s", got, prompt.SortRecent)
+	}
+	if got, want := loaded.VariableHistory("review", "lang"), []string{"go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("VariableHistory() = %q, want %q", got, want)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("state directory holds %d files, want only the state file", len(entries))
+	}
+}
+
+func TestUnreadableStateIsReplaced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+	if err := os.WriteFile(path, []byte("usage: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore(path)
+	if err := s.Load(); err == nil {
+		t.Fatal("Load() of a broken file succeeded")
+	}
+	if err := s.SetSortMode(prompt.SortPinned); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewStore(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.SortMode(); got != prompt.SortPinned {
+		t.Errorf("SortMode() = %s, want %s", got, prompt.SortPinned)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewStore("")
+	if err := s.RecordUse("review", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Usage("review").Count; got != 1 {
+		t.Errorf("Usage().Count = %d, want 1", got)
+	}
+}
@@ -26,6 +26,25 @@ func NewRepository(filePath string, service *prompt.Service) *Repository {
 }
 
 func (r *Repository) LoadPrompts() (prompt.PromptCollection, error) {
+	pc, err := r.loadRaw()
+	if err != nil {
+		return prompt.PromptCollection{}, err
+	}
+	if err := r.service.ResolveInheritance(&pc); err != nil {
+		return prompt.PromptCollection{}, fmt.Errorf("failed to resolve prompt inheritance in '%s': %w", r.filePath, err)
+	}
+	source, err := filepath.Abs(r.filePath)
+	if err != nil {
+		source = r.filePath
+	}
+	for i := range pc.Prompts {
+		pc.Prompts[i].Source = source
+	}
+	r.service.SortPromptsByTitle(&pc)
+	return pc, nil
+}
+
+func (r *Repository) loadRaw() (prompt.PromptCollection, error) {
 	data, err := os.ReadFile(r.filePath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -37,7 +56,6 @@ func (r *Repository) LoadPrompts() (prompt.PromptCollection, error) {
 	if err := yaml.Unmarshal(data, &pc); err != nil {
 		return prompt.PromptCollection{}, fmt.Errorf("failed to parse YAML from '%s': %w", r.filePath, err)
 	}
-	r.service.SortPromptsByTitle(&pc)
 	return pc, nil
 }
 
@@ -58,7 +76,7 @@ func (r *Repository) SavePrompts(collection prompt.PromptCollection) error {
 }
 
 func (r *Repository) SavePrompt(newPrompt prompt.Prompt) error {
-	collection, err := r.LoadPrompts()
+	collection, err := r.loadRaw()
 	if err != nil {
 
 		return fmt.Errorf("could not load existing prompts before saving: %w", err)
@@ -69,3 +87,50 @@ func (r *Repository) SavePrompt(newPrompt prompt.Prompt) error {
 	}
 	return nil
 }
+
+// UpdatePrompt applies update to the stored prompt referenced by ID or title
+// and saves the collection. The prompt is updated as written in the file,
+// before inheritance is resolved.
+func (r *Repository) UpdatePrompt(ref string, update func(*prompt.Prompt)) error {
+	collection, err := r.loadRaw()
+	if err != nil {
+		return fmt.Errorf("could not load existing prompts before saving: %w", err)
+	}
+
+	for i := range collection.Prompts {
+		if collection.Prompts[i].Matches(ref) {
+			update(&collection.Prompts[i])
+			if err := r.SavePrompts(collection); err != nil {
+				return fmt.Errorf("could not save updated prompts collection: %w", err)
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("prompt '%s' not found in '%s'", ref, r.filePath)
+}
+
+// RenameTag replaces the tag from with to in every stored prompt and saves
+// the collection once. Renaming to a tag that already exists merges the two.
+// It returns the number of prompts changed.
+func (r *Repository) RenameTag(from, to string) (int, error) {
+	collection, err := r.loadRaw()
+	if err != nil {
+		return 0, fmt.Errorf("could not load existing prompts before saving: %w", err)
+	}
+
+	changed := 0
+	for i := range collection.Prompts {
+		if collection.Prompts[i].RenameTag(from, to) {
+			changed++
+		}
+	}
+	if changed == 0 {
+		return 0, fmt.Errorf("tag '%s' not found in '%s'", from, r.filePath)
+	}
+
+	if err := r.SavePrompts(collection); err != nil {
+		return 0, fmt.Errorf("could not save updated prompts collection: %w", err)
+	}
+	return changed, nil
+}
@@ -19,6 +19,44 @@ type KeyMap struct {
 	Search   key.Binding
 	Tab      key.Binding
 	ShiftTab key.Binding
+	Format   key.Binding
+	PickFile key.Binding
+	Paste    key.Binding
+	Accept   key.Binding
+	Reject   key.Binding
+	Presets  key.Binding
+	SaveFile key.Binding
+	FullText key.Binding
+
+	Tags      key.Binding
+	ToggleTag key.Binding
+	TagMode   key.Binding
+	ClearTags key.Binding
+	RenameTag key.Binding
+	Folders   key.Binding
+	Move      key.Binding
+	Favorite  key.Binding
+	Sort      key.Binding
+	Stats     key.Binding
+	History   key.Binding
+	Reopen    key.Binding
+	Delete    key.Binding
+	Redact    key.Binding
+	Tokenizer key.Binding
+	Override  key.Binding
+
+	Docs       key.Binding
+	CopyDoc    key.Binding
+	CopyPrompt key.Binding
+	NextMatch  key.Binding
+	PrevMatch  key.Binding
+
+	SavePreset key.Binding
+
+	HistoryNext key.Binding
+	HistoryPrev key.Binding
+	PreviewUp   key.Binding
+	PreviewDown key.Binding
 }
 
 func New() KeyMap {
@@ -26,7 +64,7 @@ func New() KeyMap {
 		Up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
 		Down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
 		Select:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select/confirm")),
-		Copy:     key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy XML")),
+		Copy:     key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy")),
 		Back:     key.NewBinding(key.WithKeys("left", "esc"), key.WithHelp("←/esc", "back/cancel")),
 		Quit:     key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
 		Help:     key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "toggle help")),
@@ -36,6 +74,45 @@ func New() KeyMap {
 		Search:   key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
 		Tab:      key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next field")),
 		ShiftTab: key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous field")),
+		Format:   key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "toggle XML/Markdown")),
+		PickFile: key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "browse files")),
+		Paste:    key.NewBinding(key.WithKeys("ctrl+v"), key.WithHelp("ctrl+v", "paste clipboard")),
+		Accept:   key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes")),
+		Reject:   key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "no")),
+		Presets:  key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "presets")),
+		SaveFile: key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "save to file")),
+
+		SavePreset: key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "save preset")),
+
+		FullText: key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "toggle full-text search")),
+
+		Tags:      key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tags")),
+		ToggleTag: key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "select tag")),
+		TagMode:   key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "match any/all tags")),
+		ClearTags: key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "clear tags")),
+		RenameTag: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rename/merge tag")),
+		Folders:   key.NewBinding(key.WithKeys("F"), key.WithHelp("F", "folders")),
+		Move:      key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "move to folder")),
+		Favorite:  key.NewBinding(key.WithKeys("*"), key.WithHelp("*", "favorite")),
+		Sort:      key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "sort order")),
+		Stats:     key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "usage stats")),
+		History:   key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "history")),
+		Reopen:    key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit variables")),
+		Delete:    key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "delete")),
+		Redact:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "redact")),
+		Tokenizer: key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "token model")),
+		Override:  key.NewBinding(key.WithKeys("!"), key.WithHelp("!", "copy over the token limit")),
+
+		Docs:       key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "view docs")),
+		CopyDoc:    key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "copy doc only")),
+		CopyPrompt: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "copy prompt only")),
+		NextMatch:  key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next match")),
+		PrevMatch:  key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "previous match")),
+
+		HistoryNext: key.NewBinding(key.WithKeys("ctrl+n"), key.WithHelp("ctrl+n", "older value")),
+		HistoryPrev: key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "newer value")),
+		PreviewUp:   key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "scroll preview up")),
+		PreviewDown: key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdown", "scroll preview down")),
 	}
 }
 
@@ -45,9 +122,9 @@ func (k KeyMap) ShortHelp() []key.Binding {
 
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Search, k.Select},
-		{k.Copy, k.Back},
-		{k.Create},
+		{k.Up, k.Down, k.Search, k.FullText, k.Sort, k.Select},
+		{k.Copy, k.SaveFile, k.Format, k.Tokenizer, k.Presets, k.Docs, k.Back},
+		{k.Create, k.Favorite, k.Tags, k.Folders, k.Move, k.Stats, k.History},
 		{k.Help, k.Quit},
 	}
 }
@@ -0,0 +1,216 @@
+// Package mdrender renders the common subset of Markdown for the terminal:
+// headings, paragraphs, lists, block quotes, rules, fenced code and inline
+// emphasis, code and links. Anything else is shown as written.
+package mdrender
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	h1Style    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4")).Bold(true).Padding(0, 1)
+	h2Style    = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+	hStyle     = lipgloss.NewStyle().Bold(true)
+	codeStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#F2C14E"))
+	boldStyle  = lipgloss.NewStyle().Bold(true)
+	emStyle    = lipgloss.NewStyle().Italic(true)
+	linkStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#4D7EA8"))
+	faintStyle = lipgloss.NewStyle().Faint(true)
+)
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	ruleRe    = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	bulletRe  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedRe = regexp.MustCompile(`^(\s*)(\d{1,9}[.)])\s+(.*)$`)
+	fenceRe   = regexp.MustCompile("^\\s*(```+|~~~+)")
+)
+
+// IsMarkdown reports whether a file name has a Markdown extension.
+func IsMarkdown(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".md", ".markdown", ".mdown", ".mkd"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Render renders the Markdown source wrapped to width columns.
+func Render(src string, width int) string {
+	width = max(width, 10)
+	var out []string
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			out = append(out, wrap(inline(strings.Join(paragraph, " ")), width, "", "")...)
+			paragraph = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if m := fenceRe.FindStringSubmatch(line); m != nil {
+			flush()
+			fence := m[1]
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				out = append(out, "  "+codeStyle.Render(strings.ReplaceAll(lines[i], "\t", "    ")))
+			}
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+			out = append(out, "")
+		case headingRe.MatchString(trimmed):
+			flush()
+			m := headingRe.FindStringSubmatch(trimmed)
+			out = append(out, wrap(heading(len(m[1]), m[2]), width, "", "")...)
+		case ruleRe.MatchString(line):
+			flush()
+			out = append(out, faintStyle.Render(strings.Repeat("─", width)))
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			text := strings.TrimSpace(strings.TrimLeft(trimmed, "> "))
+			bar := faintStyle.Render("│ ")
+			out = append(out, wrap(faintStyle.Render(inline(text)), width, bar, bar)...)
+		case bulletRe.MatchString(line):
+			flush()
+			m := bulletRe.FindStringSubmatch(line)
+			indent := strings.Repeat(" ", len(m[1]))
+			out = append(out, wrap(inline(m[2]), width, indent+"• ", indent+"  ")...)
+		case orderedRe.MatchString(line):
+			flush()
+			m := orderedRe.FindStringSubmatch(line)
+			indent := strings.Repeat(" ", len(m[1]))
+			out = append(out, wrap(inline(m[3]), width, indent+m[2]+" ", indent+strings.Repeat(" ", len(m[2])+1))...)
+		case strings.HasPrefix(trimmed, "|"), strings.HasPrefix(line, "    "), strings.HasPrefix(line, "\t"):
+			flush()
+			out = append(out, line)
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n")
+}
+
+func heading(level int, text string) string {
+	switch level {
+	case 1:
+		return h1Style.Render(text)
+	case 2:
+		return h2Style.Render(text)
+	}
+	return hStyle.Render(strings.Repeat("#", level) + " " + text)
+}
+
+// wrap word-wraps styled text, starting the first line with first and the
+// others with rest.
+func wrap(text string, width int, first, rest string) []string {
+	wrapped := strings.Split(ansi.Wordwrap(text, max(width-ansi.StringWidth(first), 1), ""), "\n")
+	for i := range wrapped {
+		if i == 0 {
+			wrapped[i] = first + wrapped[i]
+		} else {
+			wrapped[i] = rest + wrapped[i]
+		}
+	}
+	return wrapped
+}
+
+// inline renders code spans, strong and emphasised text, links and images.
+func inline(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_[]()#!>|-", text[i+1]) >= 0:
+			b.WriteByte(text[i+1])
+			i += 2
+			continue
+		case c == '`':
+			ticks := countRun(text[i:], '`')
+			if end := strings.Index(text[i+ticks:], strings.Repeat("`", ticks)); end >= 0 {
+				b.WriteString(codeStyle.Render(strings.TrimSpace(text[i+ticks : i+ticks+end])))
+				i += ticks + end + ticks
+				continue
+			}
+		case (c == '*' || c == '_') && strings.HasPrefix(text[i:], strings.Repeat(string(c), 2)):
+			delim := strings.Repeat(string(c), 2)
+			if end := strings.Index(text[i+2:], delim); end > 0 {
+				b.WriteString(boldStyle.Render(inline(text[i+2 : i+2+end])))
+				i += 2 + end + 2
+				continue
+			}
+		case c == '*' || (c == '_' && (i == 0 || !isWordByte(text[i-1]))):
+			if end := strings.IndexByte(text[i+1:], c); end > 0 && text[i+1] != ' ' &&
+				(c == '*' || i+2+end >= len(text) || !isWordByte(text[i+2+end])) {
+				b.WriteString(emStyle.Render(text[i+1 : i+1+end]))
+				i += 1 + end + 1
+				continue
+			}
+		case c == '[' || (c == '!' && strings.HasPrefix(text[i:], "![")):
+			start := i
+			if c == '!' {
+				start++
+			}
+			if label, url, n, ok := link(text[start:]); ok {
+				if c == '!' {
+					b.WriteString(faintStyle.Render("[image: " + label + "]"))
+				} else {
+					b.WriteString(linkStyle.Render(label))
+					if url != label {
+						b.WriteString(faintStyle.Render(" (" + url + ")"))
+					}
+				}
+				i = start + n
+				continue
+			}
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String()
+}
+
+// link parses [label](url) at the start of text, returning its length.
+func link(text string) (label, url string, n int, ok bool) {
+	closeLabel := strings.Index(text, "](")
+	if closeLabel < 0 {
+		return "", "", 0, false
+	}
+	closeURL := strings.IndexByte(text[closeLabel+2:], ')')
+	if closeURL < 0 {
+		return "", "", 0, false
+	}
+	label = text[1:closeLabel]
+	url, _, _ = strings.Cut(text[closeLabel+2:closeLabel+2+closeURL], " ")
+	return label, url, closeLabel + 2 + closeURL + 1, true
+}
+
+func countRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
@@ -0,0 +1,139 @@
+package mdrender
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		width int
+		want  string
+	}{
+		{
+			name:  "headings",
+			src:   "# Title\n\n## Usage\n\n### Flags ###",
+			width: 40,
+			want:  " Title\n\nUsage\n\n### Flags",
+		},
+		{
+			name:  "fenced code keeps its lines",
+			src:   "```go\nfunc main() {\n\tfmt.Println(\"# not a heading\")\n}\n```\nafter",
+			width: 20,
+			want:  "  func main() {\n      fmt.Println(\"# not a heading\")\n  }\nafter",
+		},
+		{
+			name:  "a fence closes only with its own marker",
+			src:   "~~~\n```\n~~~\ntext",
+			width: 40,
+			want:  "  ```\ntext",
+		},
+		{
+			name:  "lists",
+			src:   "- one\n* two\n  - nested\n1. first\n10) tenth",
+			width: 40,
+			want:  "• one\n• two\n  • nested\n1. first\n10) tenth",
+		},
+		{
+			name:  "paragraph lines are joined and wrapped",
+			src:   "The quick brown fox\njumps over the lazy dog again and again",
+			width: 20,
+			want:  "The quick brown fox\njumps over the lazy\ndog again and again",
+		},
+		{
+			name:  "list items wrap under their text",
+			src:   "- The quick brown fox jumps over the lazy dog\n1. The quick brown fox jumps",
+			width: 20,
+			want:  "• The quick brown\n  fox jumps over the\n  lazy dog\n1. The quick brown\n   fox jumps",
+		},
+		{
+			name:  "block quotes wrap with their bar",
+			src:   "> quoted text that is long enough to wrap",
+			width: 20,
+			want:  "│ quoted text that\n│ is long enough to\n│ wrap",
+		},
+		{
+			name:  "inline markup",
+			src:   "a **bold** *em* `code` [link](https://x.io \"title\") [same](same) ![logo](a.png) \\*lit\\* snake_case_name",
+			width: 200,
+			want:  "a bold em code link (https://x.io) same [image: logo] *lit* snake_case_name",
+		},
+		{
+			name:  "rules, tables and indented code are kept",
+			src:   "para\n\n---\n| a | b |\n    indented\n\n\n",
+			width: 10,
+			want:  "para\n\n──────────\n| a | b |\n    indented",
+		},
+		{
+			name:  "CRLF",
+			src:   "one\r\ntwo\r\n\r\nthree",
+			width: 40,
+			want:  "one two\n\nthree",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ansi.Strip(Render(tt.src, tt.width)); got != tt.want {
+				t.Errorf("Render() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderStyledLinesStripToText(t *testing.T) {
+	previous := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(0) // termenv.TrueColor
+	defer lipgloss.SetColorProfile(previous)
+
+	src := "# Guide\n\nRun **promptgen** with `render` to see the *rendered* prompt on stdout.\n\n- a [link](https://example.com) in a list item"
+	got := Render(src, 24)
+	if !strings.Contains(got, "\x1b[") {
+		t.Fatal("Render() did not style its output")
+	}
+
+	want := []string{
+		" Guide ",
+		"",
+		"Run promptgen with",
+		"render to see the",
+		"rendered prompt on",
+		"stdout.",
+		"",
+		"• a link",
+		"  (https://example.com)",
+		"  in a list item",
+	}
+	lines := strings.Split(got, "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Render() has %d lines, want %d:\n%s", len(lines), len(want), ansi.Strip(got))
+	}
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		if plain != want[i] {
+			t.Errorf("line %d = %q, want %q", i, plain, want[i])
+		}
+		if w := ansi.StringWidth(line); w > 24 {
+			t.Errorf("line %d is %d columns wide, want at most 24", i, w)
+		}
+	}
+}
+
+func TestIsMarkdown(t *testing.T) {
+	for name, want := range map[string]bool{
+		"README.md":      true,
+		"notes.MARKDOWN": true,
+		"guide.mkd":      true,
+		"main.go":        false,
+		"md":             false,
+	} {
+		if got := IsMarkdown(name); got != want {
+			t.Errorf("IsMarkdown(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -18,6 +18,9 @@ type Styles struct {
 	InputView     lipgloss.Style
 	Footer        lipgloss.Style
 	StatusLine    lipgloss.Style
+	Placeholder   lipgloss.Style
+	ActiveValue   lipgloss.Style
+	Secret        lipgloss.Style
 }
 
 func New() Styles {
@@ -35,5 +38,8 @@ func New() Styles {
 		InputView:     lipgloss.NewStyle().Padding(0, 1),
 		Footer:        lipgloss.NewStyle().MarginTop(1),
 		StatusLine:    lipgloss.NewStyle().Height(1),
+		Placeholder:   lipgloss.NewStyle().Foreground(lipgloss.Color("#242424")).Background(lipgloss.Color("#F2C14E")),
+		ActiveValue:   lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4")),
+		Secret:        lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#FF5F87")),
 	}
 }
@@ -2,23 +2,97 @@
 package clipboard
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/atotto/clipboard"
 )
 
-// Manager provides clipboard operations
-type Manager struct{}
+// Backend names accepted by NewForBackend.
+const (
+	BackendAuto    = "auto"
+	BackendNative  = "native"
+	BackendOSC52   = "osc52"
+	BackendFile    = "file"
+	BackendCommand = "command"
+)
 
-// New creates a new clipboard manager
-func New() *Manager {
-	return &Manager{}
+// Backend is one way of handing text to the user's clipboard.
+type Backend interface {
+	Name() string
+	Copy(text string) error
 }
 
-// Copy copies text to the system clipboard
-func (m *Manager) Copy(text string) error {
-	return clipboard.WriteAll(text)
+// Manager provides clipboard operations, copying through the first of its
+// backends that succeeds
+type Manager struct {
+	backends []Backend
+}
+
+// New creates a clipboard manager trying the backends in order. Without
+// backends it uses the system clipboard only.
+func New(backends ...Backend) *Manager {
+	if len(backends) == 0 {
+		backends = []Backend{Native{}}
+	}
+	return &Manager{backends: backends}
+}
+
+// NewForBackend creates a manager for a configured backend name. "auto" (or
+// an empty name) chains the command, when one is given, the system
+// clipboard, OSC 52 and a file in filePath. OSC 52 cannot tell whether the
+// terminal accepted the sequence, so the file is only reached without a
+// terminal to send it to. No backend writes to stdout: the CLI's --print
+// flag does that instead of copying.
+func NewForBackend(name, filePath, command string) (*Manager, error) {
+	switch name {
+	case "", BackendAuto:
+		if command != "" {
+			return New(&Command{Command: command}, Native{}, NewOSC52(), &File{Path: filePath}), nil
+		}
+		return New(Native{}, NewOSC52(), &File{Path: filePath}), nil
+	case BackendCommand:
+		if command == "" {
+			return nil, errors.New("the command clipboard backend needs a command")
+		}
+		return New(&Command{Command: command}), nil
+	case BackendNative:
+		return New(Native{}), nil
+	case BackendOSC52:
+		return New(NewOSC52()), nil
+	case BackendFile:
+		return New(&File{Path: filePath}), nil
+	}
+	return nil, fmt.Errorf("unknown clipboard backend '%s' (use auto, native, osc52, file or command)", name)
+}
+
+// Copy copies text through the first backend that accepts it and returns
+// that backend
+func (m *Manager) Copy(text string) (Backend, error) {
+	var errs []error
+	for _, b := range m.backends {
+		err := b.Copy(text)
+		if err == nil {
+			return b, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", b.Name(), err))
+	}
+	return nil, errors.Join(errs...)
 }
 
 // Paste gets text from the system clipboard
 func (m *Manager) Paste() (string, error) {
 	return clipboard.ReadAll()
-}
\ No newline at end of file
+}
+
+// Native is the system clipboard, through the platform's clipboard tools.
+type Native struct{}
+
+func (Native) Name() string { return BackendNative }
+
+func (Native) Copy(text string) error {
+	if clipboard.Unsupported {
+		return errors.New("no clipboard utility available")
+	}
+	return clipboard.WriteAll(text)
+}
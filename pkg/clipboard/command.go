@@ -0,0 +1,30 @@
+package clipboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/renatogalera/promptgen/pkg/shell"
+)
+
+// Command pipes the text into a user-configured command such as wl-copy,
+// xclip -selection clipboard or pbcopy.
+type Command struct {
+	Command string
+}
+
+func (c *Command) Name() string { return BackendCommand }
+
+func (c *Command) Copy(text string) error {
+	result, err := shell.Run(c.Command, shell.Options{Stdin: text})
+	if err != nil {
+		return err
+	}
+	switch {
+	case result.TimedOut:
+		return fmt.Errorf("'%s' timed out", c.Command)
+	case result.ExitCode != 0:
+		return fmt.Errorf("'%s' exited with status %d: %s", c.Command, result.ExitCode, strings.TrimSpace(result.Output))
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// File is the last resort when no clipboard is reachable: the text is
+// written to a file the user can open or cat.
+type File struct {
+	Path string
+}
+
+// DefaultFilePath is used when File has no path.
+func DefaultFilePath() string {
+	return filepath.Join(os.TempDir(), "promptgen-clipboard.txt")
+}
+
+func (f *File) Name() string { return BackendFile }
+
+func (f *File) Copy(text string) error {
+	path := f.FilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+		return fmt.Errorf("failed to write '%s': %w", path, err)
+	}
+	return nil
+}
+
+func (f *File) FilePath() string {
+	if f.Path == "" {
+		return DefaultFilePath()
+	}
+	return f.Path
+}
@@ -0,0 +1,121 @@
+package clipboard
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// Multiplexer passthrough modes for OSC 52.
+const (
+	PassthroughNone   = "none"
+	PassthroughTmux   = "tmux"
+	PassthroughScreen = "screen"
+)
+
+const (
+	// DefaultChunkSize is how much of the encoded payload is written at a
+	// time.
+	DefaultChunkSize = 4096
+	// screenChunkSize stays under screen's limit on the length of a DCS
+	// string, so each chunk is passed through in its own.
+	screenChunkSize = 76
+)
+
+// OSC52 asks the terminal to set its clipboard with the OSC 52 escape
+// sequence. It works over SSH since the terminal, not the remote host, owns
+// the clipboard. Inside tmux or screen the sequence is wrapped so the
+// multiplexer passes it on to the outer terminal.
+type OSC52 struct {
+	Passthrough string
+	ChunkSize   int
+	// Open returns the terminal to write to; it defaults to /dev/tty.
+	Open func() (io.WriteCloser, error)
+}
+
+// NewOSC52 returns an OSC 52 backend with passthrough detected from the
+// environment.
+func NewOSC52() *OSC52 {
+	return &OSC52{Passthrough: DetectPassthrough()}
+}
+
+// DetectPassthrough reports the terminal multiplexer promptgen runs in.
+func DetectPassthrough() string {
+	switch {
+	case os.Getenv("TMUX") != "":
+		return PassthroughTmux
+	case os.Getenv("STY") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		return PassthroughScreen
+	}
+	return PassthroughNone
+}
+
+func (o *OSC52) Name() string { return BackendOSC52 }
+
+// Copy writes the sequence to the terminal. It only fails when there is no
+// terminal: whether the terminal supports OSC 52 is never reported back.
+func (o *OSC52) Copy(text string) error {
+	open := o.Open
+	if open == nil {
+		open = openTTY
+	}
+	tty, err := open()
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+
+	for _, chunk := range o.Sequence(text) {
+		if _, err := io.WriteString(tty, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sequence returns the escape sequence setting the clipboard to text, split
+// into the chunks to write.
+func (o *OSC52) Sequence(text string) []string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(text))
+
+	chunkSize := o.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if o.Passthrough == PassthroughScreen {
+		chunkSize = screenChunkSize
+	}
+	var parts []string
+	for len(encoded) > chunkSize {
+		parts = append(parts, encoded[:chunkSize])
+		encoded = encoded[chunkSize:]
+	}
+	parts = append(parts, encoded)
+
+	switch o.Passthrough {
+	case PassthroughTmux:
+		// tmux passes a DCS string through with every ESC doubled.
+		parts[0] = "\x1bPtmux;\x1b\x1b]52;c;" + parts[0]
+		parts[len(parts)-1] += "\a\x1b\\"
+	case PassthroughScreen:
+		for i := range parts {
+			parts[i] = "\x1bP" + parts[i] + "\x1b\\"
+		}
+		parts[0] = "\x1bP\x1b]52;c;\x1b\\" + parts[0]
+		parts = append(parts, "\x1bP\a\x1b\\")
+	default:
+		parts[0] = "\x1b]52;c;" + parts[0]
+		parts[len(parts)-1] += "\a"
+	}
+	return parts
+}
+
+func openTTY() (io.WriteCloser, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return nil, errors.New("no terminal to send OSC 52 to")
+	}
+	return tty, nil
+}
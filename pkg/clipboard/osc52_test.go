@@ -0,0 +1,144 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOSC52Sequence(t *testing.T) {
+	long := strings.Repeat("x", 60) // 80 base64 characters
+
+	tests := []struct {
+		name string
+		osc  OSC52
+		text string
+		want []string
+	}{
+		{
+			name: "plain",
+			text: "hi",
+			want: []string{"\x1b]52;c;aGk=\a"},
+		},
+		{
+			name: "empty",
+			text: "",
+			want: []string{"\x1b]52;c;\a"},
+		},
+		{
+			name: "chunked",
+			osc:  OSC52{ChunkSize: 4},
+			text: "hello",
+			want: []string{"\x1b]52;c;aGVs", "bG8=\a"},
+		},
+		{
+			name: "payload of exactly one chunk",
+			osc:  OSC52{ChunkSize: 8},
+			text: "hello",
+			want: []string{"\x1b]52;c;aGVsbG8=\a"},
+		},
+		{
+			name: "tmux",
+			osc:  OSC52{Passthrough: PassthroughTmux},
+			text: "hi",
+			want: []string{"\x1bPtmux;\x1b\x1b]52;c;aGk=\a\x1b\\"},
+		},
+		{
+			name: "tmux chunked",
+			osc:  OSC52{Passthrough: PassthroughTmux, ChunkSize: 4},
+			text: "hello",
+			want: []string{"\x1bPtmux;\x1b\x1b]52;c;aGVs", "bG8=\a\x1b\\"},
+		},
+		{
+			name: "screen",
+			osc:  OSC52{Passthrough: PassthroughScreen},
+			text: "hi",
+			want: []string{"\x1bP\x1b]52;c;\x1b\\\x1bPaGk=\x1b\\", "\x1bP\a\x1b\\"},
+		},
+		{
+			name: "screen keeps its own chunk size",
+			osc:  OSC52{Passthrough: PassthroughScreen, ChunkSize: 4096},
+			text: long,
+			want: []string{
+				"\x1bP\x1b]52;c;\x1b\\\x1bP" + strings.Repeat("eHh4", 19) + "\x1b\\",
+				"\x1bPeHh4\x1b\\",
+				"\x1bP\a\x1b\\",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.osc.Sequence(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sequence(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOSC52SequenceRoundTrip(t *testing.T) {
+	text := strings.Repeat("Résumé the diff below.\n", 400)
+	o := OSC52{ChunkSize: 1000}
+
+	parts := o.Sequence(text)
+	if len(parts) < 2 {
+		t.Fatalf("Sequence() wrote %d chunks, want several", len(parts))
+	}
+	joined := strings.Join(parts, "")
+	payload := strings.TrimSuffix(strings.TrimPrefix(joined, "\x1b]52;c;"), "\a")
+	decoded, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decoded) != text {
+		t.Error("decoded payload differs from the copied text")
+	}
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func TestOSC52Copy(t *testing.T) {
+	var buf bytes.Buffer
+	o := &OSC52{Open: func() (io.WriteCloser, error) { return nopCloser{&buf}, nil }}
+	if err := o.Copy("hi"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "\x1b]52;c;aGk=\a"; got != want {
+		t.Errorf("Copy() wrote %q, want %q", got, want)
+	}
+
+	o.Open = func() (io.WriteCloser, error) { return nil, errors.New("no terminal") }
+	if err := o.Copy("hi"); err == nil {
+		t.Error("Copy() without a terminal succeeded")
+	}
+}
+
+func TestDetectPassthrough(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "plain terminal", env: map[string]string{"TERM": "xterm-256color"}, want: PassthroughNone},
+		{name: "tmux", env: map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0", "TERM": "screen-256color"}, want: PassthroughTmux},
+		{name: "screen session", env: map[string]string{"STY": "1234.pts-0", "TERM": "xterm"}, want: PassthroughScreen},
+		{name: "screen terminal", env: map[string]string{"TERM": "screen"}, want: PassthroughScreen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"TMUX", "STY", "TERM"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if got := DetectPassthrough(); got != tt.want {
+				t.Errorf("DetectPassthrough() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// File: pkg/fileset/fileset.go
+package fileset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultMaxFileSize  = 256 * 1024
+	DefaultMaxTotalSize = 1024 * 1024
+	binarySniffLen      = 8000
+)
+
+// File is a text file read from disk
+type File struct {
+	Path    string
+	Content string
+}
+
+// Options limits what Collect reads
+type Options struct {
+	MaxFileSize  int64
+	MaxTotalSize int64
+}
+
+// DefaultOptions returns the default size limits
+func DefaultOptions() Options {
+	return Options{MaxFileSize: DefaultMaxFileSize, MaxTotalSize: DefaultMaxTotalSize}
+}
+
+// Collect reads the files matching pattern, which may be a file, a directory
+// or a glob supporting **. Files ignored by git, binary files and files over
+// the size limits are skipped and reported in the returned notes.
+func Collect(pattern string, opts Options) ([]File, []string, error) {
+	paths, err := expand(pattern)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(paths) == 0 {
+		return nil, nil, fmt.Errorf("no files match '%s'", pattern)
+	}
+
+	var files []File
+	var notes []string
+	var total int64
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, notes, fmt.Errorf("failed to stat '%s': %w", path, err)
+		}
+		if opts.MaxFileSize > 0 && info.Size() > opts.MaxFileSize {
+			notes = append(notes, fmt.Sprintf("skipped %s: %d bytes exceeds the %d byte file limit", path, info.Size(), opts.MaxFileSize))
+			continue
+		}
+		if opts.MaxTotalSize > 0 && total+info.Size() > opts.MaxTotalSize {
+			notes = append(notes, fmt.Sprintf("skipped %s: total size limit of %d bytes reached", path, opts.MaxTotalSize))
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, notes, fmt.Errorf("failed to read '%s': %w", path, err)
+		}
+		if IsBinary(data) {
+			notes = append(notes, fmt.Sprintf("skipped %s: binary file", path))
+			continue
+		}
+
+		total += int64(len(data))
+		files = append(files, File{Path: path, Content: string(data)})
+	}
+
+	return files, notes, nil
+}
+
+// IsBinary reports whether data looks like a binary file
+func IsBinary(data []byte) bool {
+	if len(data) > binarySniffLen {
+		data = data[:binarySniffLen]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// Expand returns the files matching pattern, which may be a file, a
+// directory or a glob supporting **, leaving out files ignored by git.
+func Expand(pattern string) ([]string, error) {
+	return expand(pattern)
+}
+
+func expand(pattern string) ([]string, error) {
+	pattern = filepath.Clean(pattern)
+
+	if !hasMeta(pattern) {
+		info, err := os.Stat(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read '%s': %w", pattern, err)
+		}
+		if !info.IsDir() {
+			return []string{pattern}, nil
+		}
+		return walk(pattern, nil)
+	}
+
+	root := baseDir(pattern)
+	re, err := regexp.Compile("^" + globToRegexp(filepath.ToSlash(pattern)) + "$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob '%s': %w", pattern, err)
+	}
+	return walk(root, re)
+}
+
+func walk(root string, match *regexp.Regexp) ([]string, error) {
+	ignore := LoadIgnore(root)
+	var paths []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				return nil
+			}
+			return err
+		}
+		if path != root && ignore.Ignored(path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		if match != nil && !match.MatchString(filepath.ToSlash(path)) {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk '%s': %w", root, err)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func hasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func baseDir(pattern string) string {
+	parts := strings.Split(filepath.ToSlash(pattern), "/")
+	var base []string
+	for _, p := range parts {
+		if hasMeta(p) {
+			break
+		}
+		base = append(base, p)
+	}
+	if len(base) == 0 {
+		return "."
+	}
+	dir := strings.Join(base, "/")
+	if dir == "" {
+		return "/"
+	}
+	return filepath.FromSlash(dir)
+}
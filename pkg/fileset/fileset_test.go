@@ -0,0 +1,270 @@
+// File: pkg/fileset/fileset_test.go
+package fileset
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// writeTree creates the given files, relative to root, with their content.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// relative returns paths relative to root, slash-separated.
+func relative(t *testing.T, root string, paths []string) []string {
+	t.Helper()
+	var out []string
+	for _, p := range paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, filepath.ToSlash(rel))
+	}
+	return out
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob string
+		path string
+		want bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "a/b/c.go", true},
+		{"docs/**", "docs/a/b.md", true},
+		{"docs/**", "src/a.md", false},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a?c", "abc", true},
+		{"a?c", "a/c", false},
+		{"[ab].md", "a.md", true},
+		{"[ab].md", "c.md", false},
+		{"[!ab].md", "c.md", true},
+		{"[!ab].md", "a.md", false},
+		{"file[.md", "file[.md", true},
+		{"a.b", "axb", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			re := regexp.MustCompile("^" + globToRegexp(tt.glob) + "$")
+			if got := re.MatchString(tt.path); got != tt.want {
+				t.Errorf("%q matches %q = %v, want %v (%s)", tt.glob, tt.path, got, tt.want, re)
+			}
+		})
+	}
+}
+
+func TestExpandGitignore(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".git/config":     "",
+		".gitignore":      "*.log\n!keep.log\n/build\ntmp/\n",
+		"main.go":         "package main\n",
+		"debug.log":       "",
+		"keep.log":        "",
+		"notes.md":        "",
+		"build/out.go":    "",
+		"tmp/scratch.txt": "",
+		"sub/.gitignore":  "*.md\n!README.md\n",
+		"sub/notes.md":    "",
+		"sub/README.md":   "",
+		"sub/build/in.go": "",
+		"sub/tmp":         "",
+		"sub/trace.log":   "",
+	})
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name:    "directory",
+			pattern: root,
+			want: []string{
+				".gitignore",
+				"keep.log",
+				"main.go",
+				"notes.md",
+				"sub/.gitignore",
+				"sub/README.md",
+				"sub/build/in.go",
+				"sub/tmp",
+			},
+		},
+		{
+			name:    "glob",
+			pattern: filepath.Join(root, "**", "*.go"),
+			want:    []string{"main.go", "sub/build/in.go"},
+		},
+		{
+			name:    "nested rules apply below their directory",
+			pattern: filepath.Join(root, "**", "*.md"),
+			want:    []string{"notes.md", "sub/README.md"},
+		},
+		{
+			name:    "single file",
+			pattern: filepath.Join(root, "main.go"),
+			want:    []string{"main.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := Expand(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := relative(t, root, paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".git/config":    "",
+		".gitignore":     "*.log\n!keep.log\n/build\ntmp/\n",
+		"sub/.gitignore": "!debug.log\n",
+	})
+	ig := LoadIgnore(root)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"debug.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"build/out.go", false, true},
+		{"sub/build", true, false},
+		{"tmp", true, true},
+		{"tmp", false, false},
+		{"a/tmp/b.txt", false, true},
+		{"sub/debug.log", false, false},
+		{"sub/deeper/debug.log", false, false},
+		{".git/config", false, true},
+		{"main.go", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ig.Ignored(filepath.Join(root, filepath.FromSlash(tt.path)), tt.isDir); got != tt.want {
+				t.Errorf("Ignored(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{name: "empty", data: nil},
+		{name: "text", data: []byte("héllo\nworld\n")},
+		{name: "NUL byte", data: []byte("PK\x03\x04\x00\x00"), want: true},
+		{name: "NUL past the sniffed prefix", data: append([]byte(strings.Repeat("a", binarySniffLen)), 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBinary(tt.data); got != tt.want {
+				t.Errorf("IsBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectLimits(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.txt":   strings.Repeat("a", 10),
+		"b.txt":   strings.Repeat("b", 10),
+		"big.txt": strings.Repeat("c", 30),
+		"bin.dat": "x\x00yz",
+	})
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantFiles []string
+		wantNotes []string
+	}{
+		{
+			name:      "no limits",
+			wantFiles: []string{"a.txt", "b.txt", "big.txt"},
+			wantNotes: []string{"bin.dat: binary file"},
+		},
+		{
+			name:      "file limit",
+			opts:      Options{MaxFileSize: 20},
+			wantFiles: []string{"a.txt", "b.txt"},
+			wantNotes: []string{"big.txt: 30 bytes exceeds the 20 byte file limit", "bin.dat: binary file"},
+		},
+		{
+			name:      "total limit",
+			opts:      Options{MaxTotalSize: 15},
+			wantFiles: []string{"a.txt"},
+			wantNotes: []string{
+				"b.txt: total size limit of 15 bytes reached",
+				"big.txt: total size limit of 15 bytes reached",
+				"bin.dat: binary file",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, notes, err := Collect(root, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, f := range files {
+				paths = append(paths, f.Path)
+			}
+			if got := relative(t, root, paths); !reflect.DeepEqual(got, tt.wantFiles) {
+				t.Errorf("Collect() files = %q, want %q", got, tt.wantFiles)
+			}
+			if len(notes) != len(tt.wantNotes) {
+				t.Fatalf("Collect() notes = %q, want %q", notes, tt.wantNotes)
+			}
+			for i, want := range tt.wantNotes {
+				if !strings.HasSuffix(notes[i], want) {
+					t.Errorf("note %d = %q, want it to end with %q", i, notes[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectNoMatch(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.txt": "a"})
+	if _, _, err := Collect(filepath.Join(root, "*.go"), DefaultOptions()); err == nil || !strings.Contains(err.Error(), "no files match") {
+		t.Errorf("Collect() error = %v, want no files match", err)
+	}
+}
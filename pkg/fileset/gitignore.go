@@ -0,0 +1,197 @@
+// File: pkg/fileset/gitignore.go
+package fileset
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type ignoreRule struct {
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Ignore matches paths against the .gitignore rules of a repository: those of
+// .git/info/exclude and the root .gitignore, and the .gitignore of every
+// directory on the way to the path, read the first time it is passed.
+type Ignore struct {
+	root string
+	// rules holds the rules of each directory by its path relative to the
+	// root, "" for the root itself.
+	rules map[string][]ignoreRule
+}
+
+// LoadIgnore reads the .gitignore and .git/info/exclude files of the root of
+// the git repository containing dir; nested .gitignore files are read as
+// paths below them are checked. Outside a repository it ignores nothing.
+func LoadIgnore(dir string) *Ignore {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return &Ignore{}
+	}
+
+	root := findGitRoot(abs)
+	if root == "" {
+		return &Ignore{}
+	}
+
+	ig := &Ignore{root: root, rules: make(map[string][]ignoreRule)}
+	ig.rules[""] = append(readRules(filepath.Join(root, ".git", "info", "exclude")), readRules(filepath.Join(root, ".gitignore"))...)
+	return ig
+}
+
+// Ignored reports whether path, or one of its parent directories, is ignored
+func (ig *Ignore) Ignored(path string, isDir bool) bool {
+	if ig.root == "" {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(ig.root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	parts := strings.Split(rel, "/")
+	for i := range parts {
+		partial := strings.Join(parts[:i+1], "/")
+		partIsDir := i < len(parts)-1 || isDir
+		if parts[i] == ".git" || ig.match(partial, partIsDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// match applies the rules of every directory above rel, outermost first, each
+// to the path relative to its directory, so deeper rules win.
+func (ig *Ignore) match(rel string, isDir bool) bool {
+	parts := strings.Split(rel, "/")
+	ignored := false
+	for i := range parts {
+		sub := strings.Join(parts[i:], "/")
+		for _, r := range ig.dirRules(strings.Join(parts[:i], "/")) {
+			if r.dirOnly && !isDir {
+				continue
+			}
+			if r.pattern.MatchString(sub) {
+				ignored = !r.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// dirRules returns the rules of the .gitignore in dir, reading it once.
+func (ig *Ignore) dirRules(dir string) []ignoreRule {
+	rules, ok := ig.rules[dir]
+	if !ok {
+		rules = readRules(filepath.Join(ig.root, filepath.FromSlash(dir), ".gitignore"))
+		ig.rules[dir] = rules
+	}
+	return rules
+}
+
+// readRules parses a gitignore file; a missing file has no rules.
+func readRules(path string) []ignoreRule {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		expr := globToRegexp(line)
+		if !anchored {
+			expr = "(?:.*/)?" + expr
+		}
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			continue
+		}
+		rule.pattern = re
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func findGitRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// globToRegexp converts a slash-separated glob supporting *, ?, [...] and **
+// into a regular expression body.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
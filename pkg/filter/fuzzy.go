@@ -0,0 +1,271 @@
+// File: pkg/filter/fuzzy.go
+package filter
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// FieldSeparator separates the fields of a filter target, most important
+// field first (for prompts: title, tags, description)
+const FieldSeparator = "\n"
+
+var fieldWeights = []int{300, 200, 100}
+
+const (
+	substringBonus  = 50
+	wordStartBonus  = 25
+	fuzzyFieldLimit = 2
+)
+
+type tokenMatch struct {
+	score   int
+	indexes []int
+}
+
+// FuzzyFilter ranks targets by how well they match every search token
+// (multi-token AND semantics). A token matching a field as a substring ranks
+// above a fuzzy subsequence match, which ranks above a match within a typo or
+// two; matches in earlier fields rank above later ones. MatchedIndexes are
+// rune offsets into the whole target, so the list highlights matches in the
+// leading title field.
+func FuzzyFilter(term string, targets []string) []list.Rank {
+	tokens := strings.Fields(strings.ToLower(term))
+	ranks := make([]list.Rank, 0, len(targets))
+	scores := make(map[int]int, len(targets))
+
+	for i, target := range targets {
+		fields := strings.Split(strings.ToLower(target), FieldSeparator)
+		total := 0
+		var matched []int
+		matchesAll := true
+
+		for _, token := range tokens {
+			best, ok := matchToken([]rune(token), fields)
+			if !ok {
+				matchesAll = false
+				break
+			}
+			total += best.score
+			matched = append(matched, best.indexes...)
+		}
+		if !matchesAll {
+			continue
+		}
+
+		ranks = append(ranks, list.Rank{Index: i, MatchedIndexes: uniqueSorted(matched)})
+		scores[i] = total
+	}
+
+	sort.SliceStable(ranks, func(a, b int) bool {
+		return scores[ranks[a].Index] > scores[ranks[b].Index]
+	})
+	return ranks
+}
+
+func matchToken(token []rune, fields []string) (tokenMatch, bool) {
+	var best tokenMatch
+	found := false
+	offset := 0
+
+	for f, field := range fields {
+		runes := []rune(field)
+		weight := fieldWeights[min(f, len(fieldWeights)-1)]
+		if f >= len(fieldWeights) {
+			weight /= 2
+		}
+
+		candidates := []func() (tokenMatch, bool){
+			func() (tokenMatch, bool) { return substringMatch(token, runes, weight) },
+			func() (tokenMatch, bool) {
+				if f >= fuzzyFieldLimit {
+					return tokenMatch{}, false
+				}
+				return subsequenceMatch(token, runes, weight)
+			},
+			func() (tokenMatch, bool) { return typoMatch(token, runes, weight) },
+		}
+		for _, candidate := range candidates {
+			if m, ok := candidate(); ok {
+				if !found || m.score > best.score {
+					for i := range m.indexes {
+						m.indexes[i] += offset
+					}
+					best = m
+					found = true
+				}
+				break
+			}
+		}
+
+		offset += len(runes) + 1
+	}
+
+	return best, found
+}
+
+func substringMatch(token, field []rune, weight int) (tokenMatch, bool) {
+	start := indexRunes(field, token)
+	if start < 0 {
+		return tokenMatch{}, false
+	}
+	score := weight + substringBonus
+	if isWordStart(field, start) {
+		score += wordStartBonus
+	}
+	return tokenMatch{score: score, indexes: runeRange(start, len(token))}, true
+}
+
+// subsequenceMatch finds the token's runes in order within the field,
+// preferring the tightest run that starts on a word boundary.
+func subsequenceMatch(token, field []rune, weight int) (tokenMatch, bool) {
+	var best tokenMatch
+	found := false
+
+	for start := range field {
+		if field[start] != token[0] {
+			continue
+		}
+		indexes := []int{start}
+		score := 0
+		if isWordStart(field, start) {
+			score += 10
+		}
+		j := start + 1
+		for t := 1; t < len(token); t++ {
+			for j < len(field) && field[j] != token[t] {
+				j++
+			}
+			if j == len(field) {
+				indexes = nil
+				break
+			}
+			if j == indexes[len(indexes)-1]+1 {
+				score += 5
+			} else if isWordStart(field, j) {
+				score += 3
+			}
+			indexes = append(indexes, j)
+			j++
+		}
+		if indexes == nil {
+			break
+		}
+		score -= indexes[len(indexes)-1] - start + 1 - len(token)
+		if !found || score > best.score {
+			best = tokenMatch{score: score, indexes: indexes}
+			found = true
+		}
+	}
+
+	if !found || best.score < 0 {
+		return tokenMatch{}, false
+	}
+	best.score = weight/2 + min(best.score, substringBonus-1)
+	return best, true
+}
+
+// typoMatch accepts a word of the field within one edit of the token, or two
+// for long tokens. Short tokens never match with typos.
+func typoMatch(token, field []rune, weight int) (tokenMatch, bool) {
+	allowed := 0
+	switch {
+	case len(token) >= 8:
+		allowed = 2
+	case len(token) >= 4:
+		allowed = 1
+	default:
+		return tokenMatch{}, false
+	}
+
+	start := -1
+	for i := 0; i <= len(field); i++ {
+		if i < len(field) && isWordRune(field[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			word := field[start:i]
+			if d := editDistance(token, word); d <= allowed {
+				return tokenMatch{score: weight/3 - d, indexes: runeRange(start, len(word))}, true
+			}
+			start = -1
+		}
+	}
+	return tokenMatch{}, false
+}
+
+// editDistance is the optimal string alignment distance: insertions,
+// deletions, substitutions and transpositions of adjacent runes.
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func indexRunes(haystack, needle []rune) int {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j := range needle {
+			if haystack[i+j] != needle[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+func isWordStart(field []rune, i int) bool {
+	return i == 0 || !isWordRune(field[i-1])
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func runeRange(start, n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = start + i
+	}
+	return indexes
+}
+
+func uniqueSorted(values []int) []int {
+	sort.Ints(values)
+	out := values[:0]
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
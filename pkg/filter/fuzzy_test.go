@@ -0,0 +1,148 @@
+// File: pkg/filter/fuzzy_test.go
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// target joins a prompt's title, tags and description as the list does.
+func target(title, tags, desc string) string {
+	return strings.Join([]string{title, tags, desc}, FieldSeparator)
+}
+
+func TestFuzzyFilterRanking(t *testing.T) {
+	tests := []struct {
+		name    string
+		term    string
+		targets []string
+		want    []int
+	}{
+		{
+			name: "title before tag before description",
+			term: "review",
+			targets: []string{
+				target("Commit message", "git", "writes a review"),
+				target("Bug report", "review", ""),
+				target("Code review", "go", ""),
+			},
+			want: []int{2, 1, 0},
+		},
+		{
+			name: "substring before abbreviation",
+			term: "rev",
+			targets: []string{
+				target("Rapid eval", "", ""),
+				target("Review", "", ""),
+			},
+			want: []int{1, 0},
+		},
+		{
+			name: "abbreviation before typo",
+			term: "revw",
+			targets: []string{
+				target("Revs", "", ""),
+				target("Review", "", ""),
+			},
+			want: []int{1, 0},
+		},
+		{
+			name: "every word must match",
+			term: "go review",
+			targets: []string{
+				target("Code review", "rust", ""),
+				target("Code review", "go", ""),
+			},
+			want: []int{1},
+		},
+		{
+			name:    "typo",
+			term:    "reveiw",
+			targets: []string{target("Summarize", "", ""), target("Code Review", "", "")},
+			want:    []int{1},
+		},
+		{
+			name:    "two typos in a long word",
+			term:    "documnetaion",
+			targets: []string{target("Write documentation", "", "")},
+			want:    []int{0},
+		},
+		{
+			name:    "short words need no typo",
+			term:    "gp",
+			targets: []string{target("Go", "", "")},
+		},
+		{
+			name:    "no match",
+			term:    "xyz",
+			targets: []string{target("Code review", "go", "")},
+		},
+		{
+			name:    "empty term keeps the order",
+			term:    " ",
+			targets: []string{target("b", "", ""), target("a", "", "")},
+			want:    []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, rank := range FuzzyFilter(tt.term, tt.targets) {
+				got = append(got, rank.Index)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FuzzyFilter(%q) = %v, want %v", tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyFilterMatchedIndexes(t *testing.T) {
+	tests := []struct {
+		name   string
+		term   string
+		target string
+		want   []int
+	}{
+		{name: "substring", term: "view", target: target("Code Review", "", ""), want: []int{7, 8, 9, 10}},
+		{name: "abbreviation", term: "cdrev", target: target("Code Review", "", ""), want: []int{0, 2, 5, 6, 7}},
+		{name: "typo marks the word", term: "reveiw", target: target("Code Review", "", ""), want: []int{5, 6, 7, 8, 9, 10}},
+		{name: "later fields are offset", term: "go", target: target("Code review", "golang", ""), want: []int{12, 13}},
+		{name: "several words", term: "review code", target: target("Code review", "", ""), want: []int{0, 1, 2, 3, 5, 6, 7, 8, 9, 10}},
+		{name: "runes, not bytes", term: "résumé", target: target("Ünïcode résumé", "", ""), want: []int{8, 9, 10, 11, 12, 13}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranks := FuzzyFilter(tt.term, []string{tt.target})
+			if len(ranks) != 1 {
+				t.Fatalf("FuzzyFilter(%q) = %v, want one match", tt.term, ranks)
+			}
+			if got := ranks[0].MatchedIndexes; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchedIndexes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"review", "review", 0},
+		{"reveiw", "review", 1},
+		{"revew", "review", 1},
+		{"review", "reviews", 1},
+		{"kitten", "sitting", 3},
+		{"", "go", 2},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// File: pkg/shell/shell.go
+package shell
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTimeout   = 10 * time.Second
+	DefaultMaxOutput = 64 * 1024
+)
+
+// Options controls how a command is run
+type Options struct {
+	Dir       string
+	Timeout   time.Duration
+	MaxOutput int
+	Stdin     string
+}
+
+// Result is the captured output of a command
+type Result struct {
+	Output    string
+	ExitCode  int
+	TimedOut  bool
+	Truncated bool
+}
+
+// Run executes command through the system shell and captures its combined
+// output. A non-zero exit status or a timeout is reported in the Result, not
+// as an error, since the output is usually still wanted.
+func Run(command string, opts Options) (Result, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxOutput <= 0 {
+		opts.MaxOutput = DefaultMaxOutput
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = opts.Dir
+	if opts.Stdin != "" {
+		cmd.Stdin = strings.NewReader(opts.Stdin)
+	}
+	// Children of the shell may keep the output pipes open after it is killed
+	cmd.WaitDelay = time.Second
+
+	out := &cappedBuffer{limit: opts.MaxOutput}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	err := cmd.Run()
+	result := Result{
+		Output:    out.buf.String(),
+		TimedOut:  errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Truncated: out.truncated,
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case result.TimedOut:
+		result.ExitCode = -1
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil && !errors.Is(err, exec.ErrWaitDelay):
+		return result, fmt.Errorf("failed to run '%s': %w", command, err)
+	}
+
+	return result, nil
+}
+
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	if room := c.limit - c.buf.Len(); room < len(p) {
+		c.truncated = true
+		if room > 0 {
+			c.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return c.buf.Write(p)
+}